@@ -0,0 +1,45 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+// +build ignore
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	ui "github.com/sparques/termui/v3"
+	"github.com/sparques/termui/v3/widgets"
+)
+
+func main() {
+	p := widgets.NewParagraph()
+	p.Title = "App"
+	p.Text = "Resize the terminal, suspend with <C-z> or quit with q."
+
+	g := widgets.NewGauge()
+	g.Title = "Progress"
+	g.BarColor = ui.ColorGreen
+
+	grid := ui.NewGrid()
+	grid.Set(
+		ui.NewRow(1.0/2, p),
+		ui.NewRow(1.0/2, g),
+	)
+
+	app := ui.NewApp(grid)
+	app.Every(100*time.Millisecond, func() {
+		percent := g.Percent + 0.01
+		if percent > 1 {
+			percent = 0
+		}
+		g.SetPercent(percent)
+		g.SetLabel(fmt.Sprintf("%.0f%%", percent*100))
+	})
+	if err := app.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
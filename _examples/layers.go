@@ -0,0 +1,61 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+// +build ignore
+
+package main
+
+import (
+	"log"
+
+	ui "github.com/sparques/termui/v3"
+	"github.com/sparques/termui/v3/widgets"
+)
+
+func main() {
+	l := widgets.NewList()
+	l.Title = "Background"
+	l.Rows = []string{
+		"[0] press p to open the popup",
+		"[1] press q to quit",
+		"[2] j and k scroll this list",
+	}
+
+	popup := widgets.NewParagraph()
+	popup.Title = "Modal"
+	popup.Text = "The layers below are dimmed.\nPress p to close."
+	popup.SetRect(10, 3, 50, 8)
+
+	// the hint only hides the cells of its text, the list shows around it
+	hint := widgets.NewParagraph()
+	hint.Border = false
+	hint.Text = "[p: popup](fg:black,bg:yellow)"
+	hint.SetRect(20, 0, 40, 1)
+
+	layers := ui.NewLayers()
+	layers.Add(l, 0).Fill = true
+	layers.Add(hint, 2).Transparent = true
+
+	app := ui.NewApp(layers)
+	app.OnEvent = func(e ui.Event) bool {
+		switch e.ID {
+		case "p":
+			if layers.Layer(popup) == nil {
+				layers.Add(popup, 1).Modal = true
+			} else {
+				layers.Remove(popup)
+			}
+		case "j":
+			l.ScrollDown()
+		case "k":
+			l.ScrollUp()
+		default:
+			return false
+		}
+		return true
+	}
+	if err := app.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -39,41 +39,32 @@ func main() {
 
 	ui.Render(l)
 
-	previousKey := ""
-	uiEvents := ui.PollEvents()
-	for {
-		e := <-uiEvents
-		switch e.ID {
-		case "q", "<C-c>":
-			return
-		case "j", "<Down>":
-			l.ScrollDown()
-		case "k", "<Up>":
-			l.ScrollUp()
-		case "<C-d>":
-			l.ScrollHalfPageDown()
-		case "<C-u>":
-			l.ScrollHalfPageUp()
-		case "<C-f>":
-			l.ScrollPageDown()
-		case "<C-b>":
-			l.ScrollPageUp()
-		case "g":
-			if previousKey == "g" {
-				l.ScrollTop()
-			}
-		case "<Home>":
-			l.ScrollTop()
-		case "G", "<End>":
-			l.ScrollBottom()
-		}
+	quit := false
+	keys := ui.NewKeymap()
+	keys.Bind("q", func(ui.Event) { quit = true })
+	keys.Bind("<C-c>", func(ui.Event) { quit = true })
+	keys.Bind("j", func(ui.Event) { l.ScrollDown() })
+	keys.Bind("<Down>", func(ui.Event) { l.ScrollDown() })
+	keys.Bind("k", func(ui.Event) { l.ScrollUp() })
+	keys.Bind("<Up>", func(ui.Event) { l.ScrollUp() })
+	keys.Bind("<C-d>", func(ui.Event) { l.ScrollHalfPageDown() })
+	keys.Bind("<C-u>", func(ui.Event) { l.ScrollHalfPageUp() })
+	keys.Bind("<C-f>", func(ui.Event) { l.ScrollPageDown() })
+	keys.Bind("<C-b>", func(ui.Event) { l.ScrollPageUp() })
+	keys.Bind("g g", func(ui.Event) { l.ScrollTop() })
+	keys.Bind("<Home>", func(ui.Event) { l.ScrollTop() })
+	keys.Bind("G", func(ui.Event) { l.ScrollBottom() })
+	keys.Bind("<End>", func(ui.Event) { l.ScrollBottom() })
+	dispatcher := ui.NewKeyDispatcher(keys)
 
-		if previousKey == "g" {
-			previousKey = ""
-		} else {
-			previousKey = e.ID
+	uiEvents := ui.PollEvents()
+	for !quit {
+		select {
+		case e := <-uiEvents:
+			dispatcher.HandleEvent(e)
+		case <-dispatcher.ChordTimer():
+			dispatcher.FlushChord()
 		}
-
 		ui.Render(l)
 	}
 }
@@ -7,15 +7,41 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
+	"os"
 
 	ui "github.com/sparques/termui/v3"
 )
 
 // logs all events to the termui window
 // stdout can also be redirected to a file and read with `tail -f`
+// with -record, events are also written to a file that can be replayed with -replay
 func main() {
+	record := flag.String("record", "", "record the events to this file")
+	replay := flag.String("replay", "", "replay the events recorded in this file")
+	flag.Parse()
+
+	if *replay != "" {
+		f, err := os.Open(*replay)
+		if err != nil {
+			log.Fatalf("failed to open recording: %v", err)
+		}
+		defer f.Close()
+		ui.SetBackend(ui.NewReplayBackend(ui.GetBackend(), f))
+	}
+
+	var recorder *ui.Recorder
+	if *record != "" {
+		f, err := os.Create(*record)
+		if err != nil {
+			log.Fatalf("failed to create recording: %v", err)
+		}
+		defer f.Close()
+		recorder = ui.NewRecorder(f)
+	}
+
 	if err := ui.Init(); err != nil {
 		log.Fatalf("failed to initialize termui: %v", err)
 	}
@@ -25,6 +51,9 @@ func main() {
 	for {
 		e := <-events
 		fmt.Printf("%v", e)
+		if recorder != nil {
+			recorder.Record(e)
+		}
 		switch e.ID {
 		case "q", "<C-c>":
 			return
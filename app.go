@@ -0,0 +1,293 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// App runs the main loop of a program: it draws Root over the whole terminal, lays it out again
+// when the terminal is resized, delivers events to OnEvent and runs periodic updates. Frames are
+// drawn by a Scheduler on the goroutine of Run, at most FrameRate times per second, and only
+// redraw the widgets that are dirty, see Block.MarkDirty, unless the App is invalidated.
+//
+// The terminal is restored when Run returns, including on SIGINT and SIGTERM. On Unix systems
+// `<C-z>` and SIGTSTP suspend the program, which resumes drawing on SIGCONT.
+type App struct {
+	sync.Mutex
+	// Root is drawn over the whole terminal. Its rectangle is set on every resize, which lays out
+	// Grids again.
+	Root Drawable
+	// OnEvent receives the events before the App handles them and reports whether it used them.
+	// KeyDispatcher.HandleEvent and FocusManager.HandleEvent can be used directly.
+	OnEvent func(Event) bool
+	// QuitKeys are the IDs of the keys stopping the App when OnEvent didn't use them. They default
+	// to `q` and `<C-c>`.
+	QuitKeys []string
+	// SuspendKeys are the IDs of the keys suspending the App like SIGTSTP. They default to `<C-z>`
+	// since the terminal doesn't send SIGTSTP itself while termui runs.
+	SuspendKeys []string
+	// FrameRate is the number of frames per second drawn at most, DefaultFrameRate by default.
+	FrameRate int
+
+	updates []*appUpdate
+	dirty   bool // Root must be drawn entirely
+	wake    chan struct{}
+	updated chan func()
+	done    chan struct{} // closed by Stop, nil while not running
+	stopped bool
+}
+
+type appUpdate struct {
+	interval time.Duration
+	update   func()
+}
+
+// SignalError is returned by App.Run when a signal stopped it.
+type SignalError struct {
+	Signal os.Signal
+}
+
+func (self SignalError) Error() string {
+	return "termui: stopped by signal: " + self.Signal.String()
+}
+
+func NewApp(root Drawable) *App {
+	return &App{
+		Root:        root,
+		QuitKeys:    []string{"q", "<C-c>"},
+		SuspendKeys: []string{"<C-z>"},
+		FrameRate:   DefaultFrameRate,
+		wake:        make(chan struct{}, 1),
+		updated:     make(chan func()),
+	}
+}
+
+// Every runs update every interval while the App runs, on the goroutine of Run so that it can
+// change widgets without locking them.
+func (self *App) Every(interval time.Duration, update func()) {
+	u := &appUpdate{
+		interval: interval,
+		update:   update,
+	}
+	self.Lock()
+	defer self.Unlock()
+	self.updates = append(self.updates, u)
+	if self.done != nil {
+		self.start(u, self.done)
+	}
+}
+
+// start sends u.update to the loop every interval until done is closed.
+func (self *App) start(u *appUpdate, done <-chan struct{}) {
+	ticker := time.NewTicker(u.interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				select {
+				case self.updated <- u.update:
+				case <-done:
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
+// Invalidate makes the App draw Root again entirely, including the widgets that aren't dirty. It
+// can be called from any goroutine.
+func (self *App) Invalidate() {
+	self.Lock()
+	self.dirty = true
+	self.Unlock()
+	select {
+	case self.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Stop makes Run return. It can be called from any goroutine.
+func (self *App) Stop() {
+	self.Lock()
+	defer self.Unlock()
+	if self.done != nil && !self.stopped {
+		self.stopped = true
+		close(self.done)
+	}
+}
+
+// Run initializes termui, runs the App until it is stopped and closes termui. It returns the
+// error of an ErrorEvent, a SignalError on SIGINT or SIGTERM, or nil after Stop or a QuitKeys key.
+func (self *App) Run() error {
+	if err := Init(); err != nil {
+		return err
+	}
+	defer Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := Subscribe(ctx).Events()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, append([]os.Signal{syscall.SIGINT, syscall.SIGTERM}, suspendSignals...)...)
+	defer signal.Stop(signals)
+
+	self.Lock()
+	done := make(chan struct{})
+	self.done, self.stopped = done, false
+	for _, u := range self.updates {
+		self.start(u, done)
+	}
+	self.Unlock()
+	defer func() {
+		self.Stop()
+		self.Lock()
+		self.done = nil
+		self.Unlock()
+	}()
+
+	scheduler := NewScheduler()
+	scheduler.FrameRate = self.FrameRate
+	schedulers.add(scheduler)
+	defer schedulers.remove(scheduler)
+
+	self.layout()
+	self.schedule(scheduler)
+	scheduler.Frame()
+	for {
+		select {
+		case e := <-events:
+			if stop, err := self.handleEvent(e); stop {
+				return err
+			}
+		case sig := <-signals:
+			switch {
+			case sig == syscall.SIGINT || sig == syscall.SIGTERM:
+				return SignalError{Signal: sig}
+			case isContinueSignal(sig):
+				self.resume()
+			default:
+				self.suspend()
+			}
+		case update := <-self.updated:
+			update()
+		case <-scheduler.FrameTimer():
+			scheduler.Frame()
+		case <-scheduler.requested:
+		case <-self.wake:
+		case <-done:
+			return nil
+		}
+		self.schedule(scheduler)
+	}
+}
+
+// handleEvent handles an event of the loop and reports whether Run must return, with its error.
+func (self *App) handleEvent(e Event) (bool, error) {
+	switch e.Type {
+	case ErrorEvent:
+		if err, ok := e.Payload.(error); ok {
+			return true, err
+		}
+	case ResizeEvent:
+		self.layout()
+	}
+	if self.OnEvent != nil && self.OnEvent(e) {
+		return false, nil
+	}
+	switch {
+	case e.Type == KeyboardEvent && containsID(self.QuitKeys, e.ID):
+		return true, nil
+	case e.Type == KeyboardEvent && containsID(self.SuspendKeys, e.ID):
+		self.suspend()
+	}
+	return false, nil
+}
+
+// layout sets the rectangle of Root to the size of the terminal and clears it.
+func (self *App) layout() {
+	if self.Root != nil {
+		width, height := TerminalDimensions()
+		self.Root.Lock()
+		self.Root.SetRect(0, 0, width, height)
+		self.Root.Unlock()
+	}
+	Clear()
+	self.Invalidate()
+}
+
+// suspend restores the terminal and stops the process like SIGTSTP, then resumes once the process
+// is continued. The events already read stay queued meanwhile.
+func (self *App) suspend() {
+	if !suspendSupported {
+		return
+	}
+	backend.Close()
+	stopProcess()
+	if err := backend.Init(); err != nil {
+		PostEvent(Event{Type: ErrorEvent, ID: "<Error>", Payload: err})
+		return
+	}
+	self.resume()
+}
+
+// resume draws the whole terminal again after the process was continued, since the terminal may
+// have been used by another program meanwhile.
+func (self *App) resume() {
+	self.layout()
+}
+
+// schedule requests a frame drawing Root entirely if the App was invalidated, or the dirty widgets
+// under Root if some of them were marked dirty by hand.
+func (self *App) schedule(scheduler *Scheduler) {
+	self.Lock()
+	dirty := self.dirty
+	self.dirty = false
+	self.Unlock()
+	if dirty {
+		if self.Root == nil {
+			scheduler.SetItems()
+		} else {
+			scheduler.SetItems(self.Root)
+		}
+		return
+	}
+	if self.Root == nil {
+		return
+	}
+	walkDrawables(self.Root, func(item Drawable) {
+		if conditional, ok := item.(ConditionalDrawable); ok {
+			item.Lock()
+			dirty = dirty || conditional.IsDirty()
+			item.Unlock()
+		}
+	})
+	if dirty {
+		scheduler.Request()
+	}
+}
+
+// walkDrawables calls f with item and, if it is a Container, all of its children. Containers are
+// locked while their children are read, item must not be locked.
+func walkDrawables(item Drawable, f func(Drawable)) {
+	f(item)
+	if container, ok := item.(Container); ok {
+		item.Lock()
+		children := container.Children()
+		item.Unlock()
+		for _, child := range children {
+			walkDrawables(child, f)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+//go:build !windows
+// +build !windows
+
+package termui
+
+import (
+	"os"
+	"syscall"
+)
+
+const suspendSupported = true
+
+var suspendSignals = []os.Signal{syscall.SIGTSTP, syscall.SIGCONT}
+
+func isContinueSignal(sig os.Signal) bool {
+	return sig == syscall.SIGCONT
+}
+
+// stopProcess stops the process group like the terminal does on Ctrl-Z, and returns once it is
+// continued.
+func stopProcess() {
+	syscall.Kill(0, syscall.SIGSTOP)
+}
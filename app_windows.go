@@ -0,0 +1,23 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package termui
+
+import (
+	"os"
+)
+
+// Windows has no job control, the App can't be suspended.
+const suspendSupported = false
+
+var suspendSignals []os.Signal
+
+func isContinueSignal(sig os.Signal) bool {
+	return false
+}
+
+func stopProcess() {}
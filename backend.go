@@ -4,32 +4,59 @@
 
 package termui
 
-import (
-	tb "github.com/nsf/termbox-go"
-)
+// Backend is the terminal termui draws to and reads events from.
+// termbox-go is used by default, but any implementation can be installed with `SetBackend`
+// before calling `Init`.
+type Backend interface {
+	// Init prepares the terminal for drawing and reading events.
+	Init() error
+	// Close restores the terminal to its original state.
+	Close()
+	// Size returns the width and height of the terminal in cells.
+	Size() (int, int)
+	// SetCell sets the cell at the given position in the backend's back buffer.
+	SetCell(x, y int, c Cell)
+	// Flush displays the contents of the back buffer.
+	Flush() error
+	// Clear resets every cell of the back buffer using the given background color.
+	Clear(bg Color) error
+	// PollEvent blocks until the next event is available and returns it.
+	PollEvent() Event
+	// Interrupt makes the pending or next call to PollEvent return an InterruptEvent.
+	Interrupt()
+}
+
+var backend Backend = NewTermboxBackend()
+
+// SetBackend replaces the backend used by termui. It must be called before `Init`.
+func SetBackend(b Backend) {
+	backend = b
+}
+
+// GetBackend returns the backend currently used by termui.
+func GetBackend() Backend {
+	return backend
+}
 
-// Init initializes termbox-go and is required to render anything.
+// Init initializes the backend and is required to render anything.
 // After initialization, the library must be finalized with `Close`.
 func Init() error {
-	if err := tb.Init(); err != nil {
-		return err
-	}
-	tb.SetInputMode(tb.InputEsc | tb.InputMouse)
-	tb.SetOutputMode(tb.Output256)
-	return nil
+	ForceRedraw()
+	return backend.Init()
 }
 
-// Close closes termbox-go.
+// Close ends every event Subscription and closes the backend.
 func Close() {
-	tb.Close()
+	hub.close()
+	backend.Close()
 }
 
 func TerminalDimensions() (int, int) {
-	tb.Sync()
-	width, height := tb.Size()
-	return width, height
+	return backend.Size()
 }
 
+// Clear clears the terminal. The next render sends every cell again.
 func Clear() {
-	tb.Clear(tb.ColorDefault, tb.Attribute(Theme.Default.Bg+1))
+	backend.Clear(Theme.Default.Bg)
+	ForceRedraw()
 }
@@ -0,0 +1,174 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"strings"
+	"sync"
+)
+
+// HeadlessBackend is an in-memory Backend that never touches a real terminal.
+// It keeps the flushed cells in memory so they can be inspected, and reads events from a queue
+// filled with `InjectEvent` instead of from the keyboard and mouse.
+// It is meant for testing dashboards and widgets:
+//
+//	screen := NewHeadlessBackend(80, 24)
+//	SetBackend(screen)
+//	Init()
+//	Render(p)
+//	cell := screen.GetCell(2, 1)
+type HeadlessBackend struct {
+	sync.Mutex
+	width     int
+	height    int
+	back      []Cell // cells set since the last flush
+	front     []Cell // cells visible after the last flush
+	events    chan Event
+	interrupt chan struct{}
+}
+
+// headlessEventQueueSize is the number of injected events that can be pending before
+// `InjectEvent` blocks.
+const headlessEventQueueSize = 256
+
+func NewHeadlessBackend(width, height int) *HeadlessBackend {
+	self := &HeadlessBackend{
+		events:    make(chan Event, headlessEventQueueSize),
+		interrupt: make(chan struct{}, 1),
+	}
+	self.resize(width, height)
+	return self
+}
+
+func (self *HeadlessBackend) resize(width, height int) {
+	self.width = width
+	self.height = height
+	self.back = make([]Cell, width*height)
+	self.front = make([]Cell, width*height)
+	for i := range self.back {
+		self.back[i] = CellClear
+		self.front[i] = CellClear
+	}
+}
+
+func (self *HeadlessBackend) Init() error {
+	return nil
+}
+
+func (self *HeadlessBackend) Close() {}
+
+func (self *HeadlessBackend) Size() (int, int) {
+	self.Lock()
+	defer self.Unlock()
+	return self.width, self.height
+}
+
+func (self *HeadlessBackend) SetCell(x, y int, c Cell) {
+	self.Lock()
+	defer self.Unlock()
+	if x < 0 || y < 0 || x >= self.width || y >= self.height {
+		return
+	}
+	self.back[y*self.width+x] = c
+}
+
+func (self *HeadlessBackend) Flush() error {
+	self.Lock()
+	defer self.Unlock()
+	copy(self.front, self.back)
+	return nil
+}
+
+func (self *HeadlessBackend) Clear(bg Color) error {
+	self.Lock()
+	defer self.Unlock()
+	cell := NewCell(' ', NewStyle(ColorClear, bg))
+	for i := range self.back {
+		self.back[i] = cell
+	}
+	return nil
+}
+
+// PollEvent blocks until an event is injected with `InjectEvent` or until `Interrupt` is called.
+func (self *HeadlessBackend) PollEvent() Event {
+	select {
+	case e := <-self.events:
+		return e
+	case <-self.interrupt:
+		return Event{Type: InterruptEvent}
+	}
+}
+
+func (self *HeadlessBackend) Interrupt() {
+	select {
+	case self.interrupt <- struct{}{}:
+	default:
+	}
+}
+
+// InjectEvent queues an event to be returned by `PollEvent`.
+// It blocks if too many events are already pending.
+func (self *HeadlessBackend) InjectEvent(e Event) {
+	self.events <- e
+}
+
+// Resize changes the size of the screen, clearing its contents, and injects the matching
+// `<Resize>` event.
+func (self *HeadlessBackend) Resize(width, height int) {
+	self.Lock()
+	self.resize(width, height)
+	self.Unlock()
+	self.InjectEvent(Event{
+		Type: ResizeEvent,
+		ID:   "<Resize>",
+		Payload: Resize{
+			Width:  width,
+			Height: height,
+		},
+	})
+}
+
+// GetCell returns the flushed cell at the given position.
+// Positions outside of the screen return CellClear.
+func (self *HeadlessBackend) GetCell(x, y int) Cell {
+	self.Lock()
+	defer self.Unlock()
+	if x < 0 || y < 0 || x >= self.width || y >= self.height {
+		return CellClear
+	}
+	return self.front[y*self.width+x]
+}
+
+// GetRune returns the rune of the flushed cell at the given position.
+func (self *HeadlessBackend) GetRune(x, y int) rune {
+	return self.GetCell(x, y).Rune
+}
+
+// GetStyle returns the Style of the flushed cell at the given position.
+func (self *HeadlessBackend) GetStyle(x, y int) Style {
+	return self.GetCell(x, y).Style
+}
+
+// GetString returns the text of the flushed cells in the given rectangle, one line per row.
+// Continuation cells of wide glyphs are left out so each line has the width of the rectangle.
+func (self *HeadlessBackend) GetString(rect image.Rectangle) string {
+	lines := []string{}
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		var sb strings.Builder
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			// continuation cells hold no text
+			sb.WriteString(self.GetCell(x, y).String())
+		}
+		lines = append(lines, sb.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// String returns the text of the whole flushed screen, one line per row.
+func (self *HeadlessBackend) String() string {
+	width, height := self.Size()
+	return self.GetString(image.Rect(0, 0, width, height))
+}
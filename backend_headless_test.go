@@ -0,0 +1,95 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"testing"
+)
+
+func TestHeadlessBackendRender(t *testing.T) {
+	screen := initHeadless(t, 8, 4)
+	b := NewBlock()
+	b.Title = "Hi"
+	b.TitleStyle = NewStyle(ColorRed, ColorClear, ModifierBold)
+	b.SetRect(0, 0, 6, 3)
+	Render(b)
+
+	want := "" +
+		"┌─Hi─┐  \n" +
+		"│    │  \n" +
+		"└────┘  \n" +
+		"        "
+	if got := screen.String(); got != want {
+		t.Errorf("screen:\n%s\nwant:\n%s", got, want)
+	}
+	if r := screen.GetRune(2, 0); r != 'H' {
+		t.Errorf("rune = %q, want H", r)
+	}
+	if s := screen.GetStyle(2, 0); s != b.TitleStyle {
+		t.Errorf("style = %+v, want %+v", s, b.TitleStyle)
+	}
+	if s := screen.GetStyle(0, 0); s != b.BorderStyle {
+		t.Errorf("border style = %+v, want %+v", s, b.BorderStyle)
+	}
+	if c := screen.GetCell(-1, 20); c != CellClear {
+		t.Errorf("cell outside of the screen = %+v, want CellClear", c)
+	}
+	if got := screen.GetString(image.Rect(2, 0, 4, 2)); got != "Hi\n  " {
+		t.Errorf("GetString = %q", got)
+	}
+}
+
+func TestHeadlessBackendWideCells(t *testing.T) {
+	screen := initHeadless(t, 4, 1)
+	b := NewBlock()
+	b.Border = false
+	b.Title = "世"
+	b.SetRect(-2, 0, 4, 1)
+	Render(b)
+
+	if got := screen.String(); got != "世  " {
+		t.Errorf("screen = %q, want %q", got, "世  ")
+	}
+	if c := screen.GetCell(1, 0); !c.IsContinuation() {
+		t.Errorf("cell after a wide glyph = %+v, want a continuation", c)
+	}
+}
+
+func TestHeadlessBackendEvents(t *testing.T) {
+	screen := initHeadless(t, 4, 2)
+	events := PollEvents()
+
+	screen.InjectEvent(NewKeyboardEvent(Key{Rune: 'q'}))
+	if e := receive(t, events); e.Type != KeyboardEvent || e.ID != "q" {
+		t.Errorf("received %v, want the q key", e)
+	}
+
+	screen.Resize(6, 3)
+	e := receive(t, events)
+	if resize, ok := e.Payload.(Resize); e.ID != "<Resize>" || !ok || resize.Width != 6 || resize.Height != 3 {
+		t.Errorf("received %v, want a resize to 6x3", e)
+	}
+	if width, height := TerminalDimensions(); width != 6 || height != 3 {
+		t.Errorf("size = %dx%d, want 6x3", width, height)
+	}
+}
+
+func TestHeadlessBackendClearRedraws(t *testing.T) {
+	screen := initHeadless(t, 4, 1)
+	b := NewBlock()
+	b.Border = false
+	b.Title = "ab"
+	b.SetRect(-2, 0, 4, 1)
+	Render(b)
+
+	// a resize clears the screen, Clear makes the next render send every cell again
+	screen.Resize(4, 1)
+	Clear()
+	Render(b)
+	if got := screen.String(); got != "ab  " {
+		t.Errorf("screen = %q, want %q", got, "ab  ")
+	}
+}
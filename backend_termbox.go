@@ -0,0 +1,212 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"bytes"
+	"os"
+
+	tb "github.com/nsf/termbox-go"
+)
+
+// TermboxBackend is the default Backend, built on top of termbox-go.
+type TermboxBackend struct {
+	// ColorMode is the set of colors sent to the terminal. Colors that the terminal doesn't
+	// support are mapped to the nearest supported color. It is detected on Init when left to
+	// ColorModeAuto, and falls back to ColorMode256 when the cells can't be written in true color,
+	// see openTerminal.
+	ColorMode ColorMode
+	// MouseMotion enables the reports of mouse moves without any button pressed, delivered as
+	// `<MouseMove>` events. termbox only reports moves while a button is pressed.
+	MouseMotion bool
+	// BracketedPaste makes the terminal mark pasted text, which is then delivered as a single
+	// `<Paste>` event instead of one keyboard event per character. It is enabled by
+	// NewTermboxBackend.
+	BracketedPaste bool
+
+	events  chan tb.Event // filled by the polling goroutine
+	polling bool
+	pending []tb.Event // events read ahead while looking for paste markers
+	pasting bool       // the rest of a long paste is still to be read
+
+	tty    *os.File     // the terminal the cells are written to, nil if termbox draws them
+	output bytes.Buffer // the cells written on the next Flush
+}
+
+const (
+	mouseMotionEnable     = "\x1b[?1003h"
+	mouseMotionDisable    = "\x1b[?1003l"
+	bracketedPasteEnable  = "\x1b[?2004h"
+	bracketedPasteDisable = "\x1b[?2004l"
+)
+
+// writeTerminal writes an escape sequence to the terminal termbox draws to.
+func writeTerminal(sequence string) {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return
+	}
+	defer tty.Close()
+	tty.WriteString(sequence)
+}
+
+func NewTermboxBackend() *TermboxBackend {
+	return &TermboxBackend{
+		ColorMode:      ColorModeAuto,
+		BracketedPaste: true,
+	}
+}
+
+// termboxOutputModeMap maps the ColorModes to the output modes of termbox, used when termbox draws
+// the cells, see writeCell.
+var termboxOutputModeMap = map[ColorMode]tb.OutputMode{
+	ColorMode16:        tb.OutputNormal,
+	ColorMode256:       tb.Output256,
+	ColorModeTrueColor: tb.Output256,
+}
+
+func (self *TermboxBackend) Init() error {
+	if err := tb.Init(); err != nil {
+		return err
+	}
+	self.pasting = false
+	if self.ColorMode == ColorModeAuto {
+		self.ColorMode = DetectColorMode()
+	}
+	if err := self.openTerminal(); err != nil && self.ColorMode == ColorModeTrueColor {
+		// termbox can't send RGB colors without replacing the palette ones
+		self.ColorMode = ColorMode256
+	}
+	tb.SetInputMode(tb.InputEsc | tb.InputMouse)
+	tb.SetOutputMode(termboxOutputModeMap[self.ColorMode])
+	if self.MouseMotion {
+		writeTerminal(mouseMotionEnable)
+	}
+	if self.BracketedPaste {
+		writeTerminal(bracketedPasteEnable)
+	}
+	return nil
+}
+
+func (self *TermboxBackend) Close() {
+	if self.MouseMotion {
+		writeTerminal(mouseMotionDisable)
+	}
+	if self.BracketedPaste {
+		writeTerminal(bracketedPasteDisable)
+	}
+	self.closeTerminal()
+	tb.Close()
+}
+
+func (self *TermboxBackend) Size() (int, int) {
+	if self.tty != nil {
+		// termbox doesn't draw the cells, so its screen must not be synced, clearing its back
+		// buffer updates the size too
+		tb.Clear(tb.ColorDefault, tb.ColorDefault)
+	} else {
+		tb.Sync()
+	}
+	width, height := tb.Size()
+	return width, height
+}
+
+// SetCell sets a cell to be drawn on the next Flush. When termbox draws the cells, only the first
+// rune of a grapheme cluster is drawn.
+func (self *TermboxBackend) SetCell(x, y int, c Cell) {
+	if self.tty != nil {
+		self.writeCell(x, y, c)
+		return
+	}
+	tb.SetCell(
+		x, y,
+		c.Rune,
+		self.termboxColor(c.Style.Fg)|termboxModifier(c.Style.Modifier), self.termboxColor(c.Style.Bg),
+	)
+}
+
+func (self *TermboxBackend) Flush() error {
+	if self.tty != nil {
+		return self.flushOutput()
+	}
+	return tb.Flush()
+}
+
+func (self *TermboxBackend) Clear(bg Color) error {
+	if self.tty != nil {
+		self.writeClear(bg)
+		return tb.Clear(tb.ColorDefault, tb.ColorDefault)
+	}
+	return tb.Clear(tb.ColorDefault, self.termboxColor(bg))
+}
+
+// PollEvent returns the next termbox event that has a termui equivalent. Errors reading the
+// terminal are returned as ErrorEvents.
+func (self *TermboxBackend) PollEvent() Event {
+	for {
+		if self.pasting {
+			if paste, ok := self.readPaste(); ok {
+				return paste
+			}
+		}
+		e, _ := self.next(0)
+		if isTermboxEsc(e) {
+			sequences := []string{backtab}
+			if self.BracketedPaste {
+				sequences = append(sequences, pasteStart)
+			}
+			read, sequence := self.expect(sequences...)
+			switch sequence {
+			case backtab:
+				return NewKeyboardEvent(Key{Code: KeyTab, Modifier: KeyModShift})
+			case pasteStart:
+				paste, _ := self.readPaste()
+				return paste
+			}
+			self.unread(read)
+		}
+		if converted, ok := convertTermboxEvent(e); ok {
+			return converted
+		}
+	}
+}
+
+func (self *TermboxBackend) Interrupt() {
+	tb.Interrupt()
+}
+
+// termboxColor converts a Color to a termbox attribute for the backend's 16 or 256 color mode.
+// termbox reserves 0 for the default color.
+func (self *TermboxBackend) termboxColor(c Color) tb.Attribute {
+	if c == ColorClear {
+		return tb.ColorDefault
+	}
+	return tb.Attribute(c.Convert(self.ColorMode) + 1)
+}
+
+// termboxModifierMap maps Modifiers to termbox attributes, used when termbox draws the cells.
+// termbox has no strikethrough or underline styles and colors, so ModifierStrikethrough is dropped,
+// the underline styles fall back to a single underline and Style.UnderlineColor is ignored.
+var termboxModifierMap = map[Modifier]tb.Attribute{
+	ModifierBold:            tb.AttrBold,
+	ModifierUnderline:       tb.AttrUnderline,
+	ModifierReverse:         tb.AttrReverse,
+	ModifierItalic:          tb.AttrCursive,
+	ModifierDim:             tb.AttrDim,
+	ModifierBlink:           tb.AttrBlink,
+	ModifierDoubleUnderline: tb.AttrUnderline,
+	ModifierCurlyUnderline:  tb.AttrUnderline,
+}
+
+// termboxModifier converts a Modifier to termbox attributes.
+func termboxModifier(m Modifier) tb.Attribute {
+	var attr tb.Attribute
+	for modifier, a := range termboxModifierMap {
+		if m&modifier != 0 {
+			attr |= a
+		}
+	}
+	return attr
+}
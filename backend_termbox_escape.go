@@ -0,0 +1,166 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"strings"
+	"time"
+
+	tb "github.com/nsf/termbox-go"
+)
+
+// termbox doesn't know some escape sequences, like Shift-Tab or the markers of bracketed paste,
+// so it reports them as an Esc key followed by characters. TermboxBackend reads ahead after every
+// Esc to find them.
+const (
+	backtab    = "[Z"
+	pasteStart = "[200~"
+	pasteEnd   = "[201~"
+)
+
+// escapeSequenceTimeout is how long to wait for the rest of an escape sequence after an Esc. The
+// characters of a sequence are sent together, so a longer wait means the Esc key was pressed.
+const escapeSequenceTimeout = 25 * time.Millisecond
+
+const (
+	// pasteTimeout is how long to wait for more pasted text before ending a paste whose end
+	// marker is missing.
+	pasteTimeout = time.Second
+	// maxPasteSize is the size of the text delivered by a paste event. Longer pastes are
+	// delivered in several events.
+	maxPasteSize = 1 << 20
+)
+
+// poll reads termbox events into the events channel until it reads an interrupt.
+func (self *TermboxBackend) poll() {
+	for {
+		e := tb.PollEvent()
+		self.events <- e
+		if e.Type == tb.EventInterrupt {
+			return
+		}
+	}
+}
+
+// next returns the next termbox event, waiting at most timeout for it if timeout isn't 0.
+// It reports false if it timed out.
+func (self *TermboxBackend) next(timeout time.Duration) (tb.Event, bool) {
+	if len(self.pending) > 0 {
+		e := self.pending[0]
+		self.pending = self.pending[1:]
+		return e, true
+	}
+	if !self.polling {
+		if self.events == nil {
+			self.events = make(chan tb.Event)
+		}
+		self.polling = true
+		go self.poll()
+	}
+	var e tb.Event
+	if timeout == 0 {
+		e = <-self.events
+	} else {
+		select {
+		case e = <-self.events:
+		case <-time.After(timeout):
+			return tb.Event{}, false
+		}
+	}
+	if e.Type == tb.EventInterrupt {
+		self.polling = false
+	}
+	return e, true
+}
+
+// unread puts events back to be returned first by next.
+func (self *TermboxBackend) unread(events []tb.Event) {
+	self.pending = append(append([]tb.Event{}, events...), self.pending...)
+}
+
+// expect reads the characters following an Esc until they form one of the given sequences, which
+// it returns, or until they can't. It also returns the events it read.
+func (self *TermboxBackend) expect(sequences ...string) ([]tb.Event, string) {
+	read := []tb.Event{}
+	var sb strings.Builder
+	for {
+		e, ok := self.next(escapeSequenceTimeout)
+		if !ok {
+			return read, ""
+		}
+		read = append(read, e)
+		if e.Type != tb.EventKey || e.Ch == 0 {
+			return read, ""
+		}
+		sb.WriteRune(e.Ch)
+		prefix := false
+		for _, sequence := range sequences {
+			if sb.String() == sequence {
+				return read, sequence
+			}
+			prefix = prefix || strings.HasPrefix(sequence, sb.String())
+		}
+		if !prefix {
+			return read, ""
+		}
+	}
+}
+
+func isTermboxEsc(e tb.Event) bool {
+	return e.Type == tb.EventKey && e.Ch == 0 && e.Key == tb.KeyEsc
+}
+
+// readPaste reads the pasted text up to the end marker and returns it as a paste event. Other
+// events received meanwhile are returned after it. Once maxPasteSize bytes are read, the text is
+// returned and the rest of the paste is read by the next call. A paste whose end marker doesn't
+// arrive ends after pasteTimeout without input. It reports false if no text was read.
+func (self *TermboxBackend) readPaste() (Event, bool) {
+	var sb strings.Builder
+	others := []tb.Event{}
+	self.pasting = true
+	for self.pasting && sb.Len() < maxPasteSize {
+		e, ok := self.next(pasteTimeout)
+		switch {
+		case !ok:
+			self.pasting = false
+		case e.Type == tb.EventInterrupt:
+			others = append(others, e)
+			self.pasting = false
+		case e.Type != tb.EventKey:
+			others = append(others, e)
+		case isTermboxEsc(e):
+			read, sequence := self.expect(pasteEnd)
+			if sequence == pasteEnd {
+				self.pasting = false
+			} else {
+				sb.WriteByte('\x1b')
+				self.unread(read)
+			}
+		default:
+			sb.WriteString(termboxKeyText(e))
+		}
+	}
+	self.unread(others)
+	return Event{
+		Type:    PasteEvent,
+		ID:      "<Paste>",
+		Payload: sb.String(),
+	}, sb.Len() > 0
+}
+
+// termboxKeyText returns the text typed by a termbox key event. Carriage returns are turned into
+// newlines.
+func termboxKeyText(e tb.Event) string {
+	switch {
+	case e.Ch != 0:
+		return string(e.Ch)
+	case e.Key == tb.KeyEnter || e.Key == tb.KeyCtrlJ:
+		return "\n"
+	case e.Key <= tb.KeySpace || e.Key == tb.KeyBackspace2:
+		return string(rune(e.Key))
+	}
+	// keys like arrows are escape sequences termbox recognized, they are not text
+	return ""
+}
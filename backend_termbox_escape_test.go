@@ -0,0 +1,103 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tb "github.com/nsf/termbox-go"
+)
+
+// newFedTermboxBackend returns a TermboxBackend reading its termbox events from the returned
+// channel instead of the terminal.
+func newFedTermboxBackend() (*TermboxBackend, chan tb.Event) {
+	events := make(chan tb.Event)
+	b := NewTermboxBackend()
+	b.events = events
+	b.polling = true
+	return b, events
+}
+
+// feed sends termbox key events typing the text, with Esc for '\x1b', then the other events.
+func feed(events chan<- tb.Event, text string, others ...tb.Event) {
+	for _, r := range text {
+		switch r {
+		case '\x1b':
+			events <- tb.Event{Type: tb.EventKey, Key: tb.KeyEsc}
+		case '\n':
+			events <- tb.Event{Type: tb.EventKey, Key: tb.KeyEnter}
+		default:
+			events <- tb.Event{Type: tb.EventKey, Ch: r}
+		}
+	}
+	for _, e := range others {
+		events <- e
+	}
+}
+
+func TestTermboxBackendPaste(t *testing.T) {
+	b, events := newFedTermboxBackend()
+	mouse := tb.Event{Type: tb.EventMouse, Key: tb.MouseLeft, MouseX: 1, MouseY: 2}
+	go func() {
+		feed(events, "\x1b[200~hello\n", mouse)
+		feed(events, "\x1bworld\x1b[201~q")
+	}()
+
+	if e := b.PollEvent(); e.Type != PasteEvent || e.Payload != "hello\n\x1bworld" {
+		t.Errorf("paste event = %v, %q", e.Type, e.Payload)
+	}
+	if e := b.PollEvent(); e.ID != "<MouseLeft>" {
+		t.Errorf("event after the paste = %q, want the mouse event read during the paste", e.ID)
+	}
+	if e := b.PollEvent(); e.ID != "q" {
+		t.Errorf("event after the paste = %q, want q", e.ID)
+	}
+}
+
+func TestTermboxBackendPasteWithoutEndMarker(t *testing.T) {
+	b, events := newFedTermboxBackend()
+	go feed(events, "\x1b[200~partial")
+
+	start := time.Now()
+	if e := b.PollEvent(); e.Type != PasteEvent || e.Payload != "partial" {
+		t.Errorf("paste event = %v, %q", e.Type, e.Payload)
+	}
+	if elapsed := time.Since(start); elapsed < pasteTimeout {
+		t.Errorf("paste ended after %v, before the timeout", elapsed)
+	}
+
+	go feed(events, "q")
+	if e := b.PollEvent(); e.Type != KeyboardEvent || e.ID != "q" {
+		t.Errorf("event after the paste = %v %q, want the q key", e.Type, e.ID)
+	}
+}
+
+func TestTermboxBackendLongPaste(t *testing.T) {
+	b, events := newFedTermboxBackend()
+	text := strings.Repeat("0123456789abcdef", maxPasteSize/16+1)
+	go feed(events, "\x1b[200~"+text+"\x1b[201~q")
+
+	first := b.PollEvent()
+	if first.Type != PasteEvent || len(first.Payload.(string)) != maxPasteSize {
+		t.Fatalf("first paste event = %v of %d bytes, want %d bytes", first.Type, len(first.Payload.(string)), maxPasteSize)
+	}
+	second := b.PollEvent()
+	if second.Type != PasteEvent || first.Payload.(string)+second.Payload.(string) != text {
+		t.Errorf("second paste event = %v, %q", second.Type, second.Payload)
+	}
+	if e := b.PollEvent(); e.ID != "q" {
+		t.Errorf("event after the paste = %q, want q", e.ID)
+	}
+}
+
+func TestTermboxBackendBacktab(t *testing.T) {
+	b, events := newFedTermboxBackend()
+	go feed(events, "\x1b[Z")
+	if e := b.PollEvent(); e.ID != "<S-<Tab>>" {
+		t.Errorf("event = %q, want <S-<Tab>>", e.ID)
+	}
+}
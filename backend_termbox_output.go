@@ -0,0 +1,142 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// termbox draws a single rune per cell, which drops the rest of grapheme clusters, and its RGB
+// output mode sends every color as RGB, which replaces the palette colors of the terminal theme with
+// the ones of xterm. TermboxBackend writes the cells to the terminal itself instead: whole grapheme
+// clusters, palette colors as palette indices and, in true color mode, the colors created with
+// NewRGBColor as RGB. termbox still reads the input and sets the terminal up, and draws the cells
+// when the terminal can't be opened.
+
+// sgrModifiers are the SGR parameters of the Modifiers. Only the first of the underline styles set
+// is sent.
+var sgrModifiers = []struct {
+	modifier  Modifier
+	parameter string
+}{
+	{ModifierBold, "1"},
+	{ModifierDim, "2"},
+	{ModifierItalic, "3"},
+	{ModifierCurlyUnderline, "4:3"},
+	{ModifierDoubleUnderline, "4:2"},
+	{ModifierUnderline, "4"},
+	{ModifierBlink, "5"},
+	{ModifierReverse, "7"},
+	{ModifierStrikethrough, "9"},
+}
+
+// underlineModifiers are the Modifiers drawing an underline.
+const underlineModifiers = ModifierUnderline | ModifierDoubleUnderline | ModifierCurlyUnderline
+
+// sgrColor returns the SGR parameters of a foreground color, or of a background color if bg is
+// set.
+func sgrColor(c Color, bg bool) string {
+	base := 30
+	if bg {
+		base = 40
+	}
+	switch {
+	case c.IsRGB():
+		r, g, b := c.RGB()
+		return fmt.Sprintf("%d;2;%d;%d;%d", base+8, r, g, b)
+	case c < 8:
+		return strconv.Itoa(base + int(c))
+	case c < 16:
+		return strconv.Itoa(base + 60 + int(c) - 8)
+	}
+	return fmt.Sprintf("%d;5;%d", base+8, c)
+}
+
+// sgrUnderlineColor returns the SGR parameters of an underline color, which has no parameters of
+// its own for the first 16 colors.
+func sgrUnderlineColor(c Color) string {
+	if c.IsRGB() {
+		r, g, b := c.RGB()
+		return fmt.Sprintf("58;2;%d;%d;%d", r, g, b)
+	}
+	return fmt.Sprintf("58;5;%d", c)
+}
+
+// sgr returns the escape sequence setting a style, starting from the default style, with the
+// colors converted to a ColorMode.
+func sgr(s Style, mode ColorMode) string {
+	parameters := []string{"0"}
+	underlined := false
+	for _, m := range sgrModifiers {
+		if s.Modifier&m.modifier == 0 || (underlined && m.modifier&underlineModifiers != 0) {
+			continue
+		}
+		parameters = append(parameters, m.parameter)
+		underlined = underlined || m.modifier&underlineModifiers != 0
+	}
+	if s.Fg != ColorClear {
+		parameters = append(parameters, sgrColor(s.Fg.Convert(mode), false))
+	}
+	if s.Bg != ColorClear {
+		parameters = append(parameters, sgrColor(s.Bg.Convert(mode), true))
+	}
+	if underlined && s.UnderlineColor() != ColorClear {
+		parameters = append(parameters, sgrUnderlineColor(s.UnderlineColor().Convert(mode)))
+	}
+	return "\x1b[" + strings.Join(parameters, ";") + "m"
+}
+
+// openTerminal opens the terminal termbox draws to, for writing the cells. It fails where there is
+// no /dev/tty, like on Windows, and termbox draws the cells then.
+func (self *TermboxBackend) openTerminal() error {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	self.tty = tty
+	self.output = bytes.Buffer{}
+	return nil
+}
+
+func (self *TermboxBackend) closeTerminal() {
+	if self.tty != nil {
+		self.tty.Close()
+		self.tty = nil
+	}
+}
+
+// writeCell adds a cell to the output sent on the next Flush. Continuation cells are left to the
+// wide glyph before them.
+func (self *TermboxBackend) writeCell(x, y int, c Cell) {
+	text := c.Grapheme
+	if text == "" {
+		if c.Rune == 0 {
+			return
+		}
+		text = string(c.Rune)
+	}
+	fmt.Fprintf(&self.output, "\x1b[%d;%dH%s%s", y+1, x+1, sgr(c.Style, self.ColorMode), text)
+}
+
+// writeClear adds the clearing of the screen with the background color to the output.
+func (self *TermboxBackend) writeClear(bg Color) {
+	self.output.Reset()
+	self.output.WriteString(sgr(Style{Fg: ColorClear, Bg: bg}, self.ColorMode) + "\x1b[2J")
+}
+
+// flushOutput writes the output to the terminal.
+func (self *TermboxBackend) flushOutput() error {
+	if self.output.Len() == 0 {
+		return nil
+	}
+	self.output.WriteString(sgr(StyleClear, self.ColorMode))
+	_, err := self.tty.Write(self.output.Bytes())
+	self.output.Reset()
+	return err
+}
@@ -0,0 +1,58 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "testing"
+
+func TestSGRKeepsPaletteColors(t *testing.T) {
+	tests := []struct {
+		style Style
+		want  string
+	}{
+		{StyleClear, "\x1b[0m"},
+		{NewStyle(ColorRed), "\x1b[0;31m"},
+		{NewStyle(ColorRed, ColorBlue), "\x1b[0;31;44m"},
+		{NewStyle(Color(9), Color(12)), "\x1b[0;91;104m"},
+		{NewStyle(Color(208)), "\x1b[0;38;5;208m"},
+		{NewStyle(NewRGBColor(255, 135, 0), Color(208)), "\x1b[0;38;2;255;135;0;48;5;208m"},
+		{NewStyle(ColorClear, ColorClear, ModifierBold|ModifierCurlyUnderline), "\x1b[0;1;4:3m"},
+		{NewStyle(ColorClear, ColorClear, ModifierDoubleUnderline), "\x1b[0;4:2m"},
+		{NewStyle(ColorClear, ColorClear, ModifierUnderline|ModifierDoubleUnderline), "\x1b[0;4:2m"},
+		{NewStyle(ColorRed, ColorClear, ModifierStrikethrough|ModifierDim|ModifierItalic), "\x1b[0;2;3;9;31m"},
+		{NewStyle(ColorClear, ColorClear, ModifierBlink|ModifierReverse), "\x1b[0;5;7m"},
+		{NewStyle(ColorClear, ColorClear, ModifierUnderline, ColorRed), "\x1b[0;4;58;5;1m"},
+		{NewStyle(ColorClear, ColorClear, ModifierCurlyUnderline, NewRGBColor(255, 0, 0)), "\x1b[0;4:3;58;2;255;0;0m"},
+		{NewStyle(ColorClear, ColorClear, ModifierBold, ColorRed), "\x1b[0;1m"},
+	}
+	for _, test := range tests {
+		if got := sgr(test.style, ColorModeTrueColor); got != test.want {
+			t.Errorf("sgr(%v) = %q, want %q", test.style, got, test.want)
+		}
+	}
+}
+
+func TestSGRConvertsColors(t *testing.T) {
+	red := NewRGBColor(255, 0, 0)
+	if got, want := sgr(NewStyle(red), ColorMode256), "\x1b[0;38;5;196m"; got != want {
+		t.Errorf("256 colors: got %q, want %q", got, want)
+	}
+	if got, want := sgr(NewStyle(Color(208), red), ColorMode16), "\x1b[0;33;101m"; got != want {
+		t.Errorf("16 colors: got %q, want %q", got, want)
+	}
+}
+
+func TestWriteCellWritesGraphemeClusters(t *testing.T) {
+	for _, mode := range []ColorMode{ColorMode16, ColorMode256, ColorModeTrueColor} {
+		b := &TermboxBackend{ColorMode: mode}
+		b.writeCell(0, 0, NewGraphemeCell("e\u0301"))
+		b.writeCell(1, 0, NewGraphemeCell("🇫🇷"))
+		b.writeCell(2, 0, NewCell(continuationRune))
+		b.writeCell(3, 1, NewGraphemeCell("\U0001F469\u200d\U0001F4BB"))
+		want := "\x1b[1;1H\x1b[0me\u0301" + "\x1b[1;2H\x1b[0m🇫🇷" + "\x1b[2;4H\x1b[0m\U0001F469\u200d\U0001F4BB"
+		if got := b.output.String(); got != want {
+			t.Errorf("mode %v: got %q, want %q", mode, got, want)
+		}
+	}
+}
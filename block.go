@@ -7,6 +7,7 @@ package termui
 import (
 	"image"
 	"sync"
+	"sync/atomic"
 )
 
 // Block is the base struct inherited by most widgets.
@@ -30,9 +31,19 @@ type Block struct {
 	TitleAlignment Alignment
 	ShowTitle      bool
 
+	// FocusedBorderStyle and FocusedTitleStyle replace BorderStyle and TitleStyle while the block
+	// has the focus, see FocusManager.
+	FocusedBorderStyle Style
+	FocusedTitleStyle  Style
+	focused            bool
+
 	// Dirty is a bool to track whether or not unrendered changes have been made
-	// to a block--it is up to the user to manage this
+	// to a block. It must be set with the block locked after changing fields directly, or
+	// MarkDirty can be called instead.
 	Dirty bool
+	// marked is set atomically by MarkDirty, so that the block can be marked from any goroutine,
+	// even while it is locked.
+	marked int32
 
 	sync.Mutex
 }
@@ -48,12 +59,19 @@ func NewBlock() *Block {
 
 		TitleStyle: Theme.Block.Title,
 		ShowTitle:  true,
+
+		FocusedBorderStyle: Theme.Block.FocusedBorder,
+		FocusedTitleStyle:  Theme.Block.FocusedTitle,
 	}
 }
 
 func (self *Block) drawBorder(buf *Buffer) {
-	verticalCell := Cell{VERTICAL_LINE, self.BorderStyle}
-	horizontalCell := Cell{HORIZONTAL_LINE, self.BorderStyle}
+	style := self.BorderStyle
+	if self.focused {
+		style = self.FocusedBorderStyle
+	}
+	verticalCell := NewCell(VERTICAL_LINE, style)
+	horizontalCell := NewCell(HORIZONTAL_LINE, style)
 
 	// draw lines
 	if self.BorderTop {
@@ -72,30 +90,30 @@ func (self *Block) drawBorder(buf *Buffer) {
 	// draw corners
 	if self.BorderTop && self.BorderLeft {
 		if self.BorderRound {
-			buf.SetCell(Cell{TOP_LEFT_ROUND, self.BorderStyle}, self.Min)
+			buf.SetCell(NewCell(TOP_LEFT_ROUND, style), self.Min)
 		} else {
-			buf.SetCell(Cell{TOP_LEFT, self.BorderStyle}, self.Min)
+			buf.SetCell(NewCell(TOP_LEFT, style), self.Min)
 		}
 	}
 	if self.BorderTop && self.BorderRight {
 		if self.BorderRound {
-			buf.SetCell(Cell{TOP_RIGHT_ROUND, self.BorderStyle}, self.Min)
+			buf.SetCell(NewCell(TOP_RIGHT_ROUND, style), self.Min)
 		} else {
-			buf.SetCell(Cell{TOP_RIGHT, self.BorderStyle}, image.Pt(self.Max.X-1, self.Min.Y))
+			buf.SetCell(NewCell(TOP_RIGHT, style), image.Pt(self.Max.X-1, self.Min.Y))
 		}
 	}
 	if self.BorderBottom && self.BorderLeft {
 		if self.BorderRound {
-			buf.SetCell(Cell{BOTTOM_LEFT_ROUND, self.BorderStyle}, self.Min)
+			buf.SetCell(NewCell(BOTTOM_LEFT_ROUND, style), self.Min)
 		} else {
-			buf.SetCell(Cell{BOTTOM_LEFT, self.BorderStyle}, image.Pt(self.Min.X, self.Max.Y-1))
+			buf.SetCell(NewCell(BOTTOM_LEFT, style), image.Pt(self.Min.X, self.Max.Y-1))
 		}
 	}
 	if self.BorderBottom && self.BorderRight {
 		if self.BorderRound {
-			buf.SetCell(Cell{BOTTOM_RIGHT_ROUND, self.BorderStyle}, self.Min)
+			buf.SetCell(NewCell(BOTTOM_RIGHT_ROUND, style), self.Min)
 		} else {
-			buf.SetCell(Cell{BOTTOM_RIGHT, self.BorderStyle}, self.Max.Sub(image.Pt(1, 1)))
+			buf.SetCell(NewCell(BOTTOM_RIGHT, style), self.Max.Sub(image.Pt(1, 1)))
 		}
 	}
 }
@@ -104,12 +122,53 @@ func (self *Block) GetTitle() string {
 	return self.Title
 }
 
+// SetTitle sets the title and marks the block dirty.
+func (self *Block) SetTitle(title string) {
+	self.Lock()
+	self.Title = title
+	self.Unlock()
+	self.MarkDirty()
+}
+
+// SetFocused implements the Focusable interface.
+func (self *Block) SetFocused(focused bool) {
+	self.Lock()
+	changed := focused != self.focused
+	self.focused = focused
+	self.Unlock()
+	if changed {
+		self.MarkDirty()
+	}
+}
+
+// IsFocused implements the Focusable interface.
+func (self *Block) IsFocused() bool {
+	self.Lock()
+	defer self.Unlock()
+	return self.focused
+}
+
 func (self *Block) IsDirty() bool {
-	return self.Dirty
+	return self.Dirty || atomic.LoadInt32(&self.marked) != 0
 }
 
 func (self *Block) Clean() {
-	self.Dirty = false
+	atomic.StoreInt32(&self.marked, 0)
+	if self.Dirty {
+		self.Dirty = false
+	}
+}
+
+// MarkDirty marks the block as changed and requests a frame from the running Schedulers, which
+// redraw it. The setters and update methods of the widgets call it, it must be called by hand
+// after changing fields directly. It can be called from any goroutine.
+//
+// The setters, like Gauge.SetPercent, lock the widget, so they can be called while a Scheduler
+// draws on another goroutine. Fields and the other methods must then be changed with the widget
+// locked.
+func (self *Block) MarkDirty() {
+	atomic.StoreInt32(&self.marked, 1)
+	requestFrames()
 }
 
 // Draw implements the Drawable interface.
@@ -123,11 +182,12 @@ func (self *Block) Draw(buf *Buffer) {
 
 	width := self.Dx()
 
-	titleCells := ParseStyles(self.Title, self.TitleStyle)
-	if len(titleCells) > width-4 {
-		titleCells = titleCells[:width-3]
-		titleCells[width-4] = Cell{ELLIPSES, self.TitleStyle}
+	titleStyle := self.TitleStyle
+	if self.focused {
+		titleStyle = self.FocusedTitleStyle
 	}
+	titleCells := TrimCells(ParseStyles(self.Title, titleStyle), width-3)
+	titleWidth := CellsWidth(titleCells)
 
 	switch self.TitleAlignment {
 	case AlignLeft:
@@ -138,28 +198,39 @@ func (self *Block) Draw(buf *Buffer) {
 	case AlignCenter:
 		buf.SetCells(
 			titleCells,
-			image.Pt(self.Min.X+(width-len(titleCells))/2, self.Min.Y),
+			image.Pt(self.Min.X+(width-titleWidth)/2, self.Min.Y),
 		)
 	case AlignRight:
 		buf.SetCells(
 			titleCells,
-			image.Pt(self.Max.X-len(titleCells)-2, self.Min.Y),
+			image.Pt(self.Max.X-titleWidth-2, self.Min.Y),
 		)
 	}
 }
 
-// SetRect implements the Drawable interface.
+// SetRect implements the Drawable interface. The block is marked dirty if the rectangle changes.
 func (self *Block) SetRect(x1, y1, x2, y2 int) {
-	self.Rectangle = image.Rect(x1, y1, x2, y2)
+	r := image.Rect(x1, y1, x2, y2)
+	changed := r != self.Rectangle
+	self.Rectangle = r
 	self.Inner = image.Rect(
 		self.Min.X+1+self.PaddingLeft,
 		self.Min.Y+1+self.PaddingTop,
 		self.Max.X-1-self.PaddingRight,
 		self.Max.Y-1-self.PaddingBottom,
 	)
+	if changed {
+		self.MarkDirty()
+	}
 }
 
 // GetRect implements the Drawable interface.
 func (self *Block) GetRect() image.Rectangle {
 	return self.Rectangle
 }
+
+// GetInnerRect returns Inner, the area inside the border and padding. It implements the Container
+// interface for the widgets holding children.
+func (self *Block) GetInnerRect() image.Rectangle {
+	return self.Inner
+}
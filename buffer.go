@@ -8,12 +8,17 @@ import (
 	"image"
 
 	rw "github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
 )
 
 // Cell represents a viewable terminal cell
 type Cell struct {
 	Rune  rune
 	Style Style
+	// Grapheme holds the whole grapheme cluster when it is made of more than one rune, like a
+	// flag, an emoji with a skin tone or a letter followed by combining marks.
+	// Rune is then the first rune of the cluster. Grapheme is empty for single rune cells.
+	Grapheme string
 }
 
 var CellClear = Cell{
@@ -21,6 +26,39 @@ var CellClear = Cell{
 	Style: StyleClear,
 }
 
+// continuationRune marks a continuation cell. It matches what termbox uses for the second
+// column of wide runes.
+const continuationRune rune = 0
+
+// IsContinuation reports whether the cell is covered by a wide glyph in a cell to its left.
+// Continuation cells are created by Buffer for every column of a glyph after the first one and
+// are never drawn on their own.
+func (self Cell) IsContinuation() bool {
+	return self.Rune == continuationRune
+}
+
+// Width returns the number of columns the cell occupies, which is 0 for continuation cells.
+func (self Cell) Width() int {
+	if self.IsContinuation() {
+		return 0
+	}
+	if self.Grapheme != "" {
+		return MaxInt(graphemeWidth(self.Grapheme), 1)
+	}
+	return MaxInt(rw.RuneWidth(self.Rune), 1)
+}
+
+// String returns the grapheme cluster displayed by the cell.
+func (self Cell) String() string {
+	if self.IsContinuation() {
+		return ""
+	}
+	if self.Grapheme != "" {
+		return self.Grapheme
+	}
+	return string(self.Rune)
+}
+
 // NewCell takes 1 to 2 arguments
 // 1st argument = rune
 // 2nd argument = optional style
@@ -35,48 +73,203 @@ func NewCell(rune rune, args ...interface{}) Cell {
 	}
 }
 
+// NewGraphemeCell takes 1 to 2 arguments
+// 1st argument = grapheme cluster, an empty one gives a space
+// 2nd argument = optional style
+func NewGraphemeCell(grapheme string, args ...interface{}) Cell {
+	style := StyleClear
+	if len(args) == 1 {
+		style = args[0].(Style)
+	}
+	cell := Cell{
+		Rune:  ' ',
+		Style: style,
+	}
+	for i, r := range grapheme {
+		if i == 0 {
+			cell.Rune = r
+		} else {
+			cell.Grapheme = grapheme
+			break
+		}
+	}
+	return cell
+}
+
 // Buffer represents a section of a terminal and is a renderable rectangle of cells.
+// Cells are stored row-major in a slice covering exactly the Rectangle; writes outside of the
+// Rectangle are dropped.
+// A wide glyph is stored as its own cell followed by continuation cells. Overwriting any part
+// of a wide glyph blanks the rest of it, and a wide glyph that doesn't fit is replaced by a space.
 type Buffer struct {
 	image.Rectangle
-	CellMap map[image.Point]Cell
+	Cells []Cell
 }
 
 func NewBuffer(r image.Rectangle) *Buffer {
+	r = r.Canon()
 	buf := &Buffer{
 		Rectangle: r,
-		CellMap:   make(map[image.Point]Cell),
+		Cells:     make([]Cell, r.Dx()*r.Dy()),
 	}
 	buf.Fill(CellClear, r) // clears out area
 	return buf
 }
 
+// index returns the position of the cell at p in Cells. p must be inside the Rectangle.
+func (self *Buffer) index(p image.Point) int {
+	return (p.Y-self.Min.Y)*self.Dx() + (p.X - self.Min.X)
+}
+
+// GetCell returns the cell at p, or CellClear if p is outside of the Buffer.
 func (self *Buffer) GetCell(p image.Point) Cell {
-	return self.CellMap[p]
+	if !p.In(self.Rectangle) {
+		return CellClear
+	}
+	return self.Cells[self.index(p)]
+}
+
+// clearWide blanks every column of the wide glyph covering p, if any, so that no half of it is
+// left behind when p is overwritten.
+func (self *Buffer) clearWide(p image.Point) {
+	i := self.index(p)
+	if self.Cells[i].Width() <= 1 && !self.Cells[i].IsContinuation() {
+		return
+	}
+	start := i
+	for x := p.X; x > self.Min.X && self.Cells[start].IsContinuation(); x-- {
+		start--
+	}
+	blank := NewCell(' ', self.Cells[start].Style)
+	self.Cells[start] = blank
+	end := i - (p.X - self.Min.X) + self.Dx()
+	for j := start + 1; j < end && self.Cells[j].IsContinuation(); j++ {
+		self.Cells[j] = blank
+	}
 }
 
 func (self *Buffer) SetCell(c Cell, p image.Point) {
-	self.CellMap[p] = c
+	if !p.In(self.Rectangle) {
+		return
+	}
+	if c.IsContinuation() {
+		c.Rune = ' '
+	}
+	width := c.Width()
+	if p.X+width > self.Max.X {
+		c = NewCell(' ', c.Style)
+		width = 1
+	}
+	self.clearWide(p)
+	self.Cells[self.index(p)] = c
+	for x := p.X + 1; x < p.X+width; x++ {
+		q := image.Pt(x, p.Y)
+		self.clearWide(q)
+		self.Cells[self.index(q)] = NewCell(continuationRune, c.Style)
+	}
 }
 
 func (self *Buffer) Fill(c Cell, rect image.Rectangle) {
-	for x := rect.Min.X; x < rect.Max.X; x++ {
+	rect = rect.Intersect(self.Rectangle)
+	if rect.Empty() {
+		return
+	}
+	if c.Width() > 1 {
 		for y := rect.Min.Y; y < rect.Max.Y; y++ {
-			self.SetCell(c, image.Pt(x, y))
+			x := rect.Min.X
+			for ; x+c.Width() <= rect.Max.X; x += c.Width() {
+				self.SetCell(c, image.Pt(x, y))
+			}
+			for ; x < rect.Max.X; x++ {
+				self.SetCell(NewCell(' ', c.Style), image.Pt(x, y))
+			}
 		}
+		return
+	}
+	// only wide glyphs crossing the edges of rect can be partly overwritten
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		self.clearWide(image.Pt(rect.Min.X, y))
+		self.clearWide(image.Pt(rect.Max.X-1, y))
+	}
+	// fill the first row, then copy it to the others
+	first := self.Cells[self.index(rect.Min) : self.index(rect.Min)+rect.Dx()]
+	for i := range first {
+		first[i] = c
+	}
+	for y := rect.Min.Y + 1; y < rect.Max.Y; y++ {
+		i := self.index(image.Pt(rect.Min.X, y))
+		copy(self.Cells[i:i+rect.Dx()], first)
 	}
 }
 
 func (self *Buffer) SetString(s string, style Style, p image.Point) {
-	runes := []rune(s)
-	x := 0
-	for _, char := range runes {
-		self.SetCell(Cell{char, style}, image.Pt(p.X+x, p.Y))
-		x += rw.RuneWidth(char)
+	x := p.X
+	state := -1
+	for len(s) > 0 && x < self.Max.X {
+		var cluster string
+		cluster, s, _, state = uniseg.FirstGraphemeClusterInString(s, state)
+		cell := NewGraphemeCell(cluster, style)
+		self.SetCell(cell, image.Pt(x, p.Y))
+		x += cell.Width()
 	}
 }
 
+// SetCells sets the cells in a row starting at p, advancing by the width of each cell.
+// Continuation cells in c are skipped since the wide cells before them already cover them.
 func (self *Buffer) SetCells(c []Cell, p image.Point) {
-	for i, c := range c {
-		self.SetCell(c, image.Pt(p.X+i, p.Y))
+	if p.Y < self.Min.Y || p.Y >= self.Max.Y {
+		return
 	}
+	x := p.X
+	for _, cell := range c {
+		if x >= self.Max.X {
+			break
+		}
+		if cell.IsContinuation() {
+			continue
+		}
+		self.SetCell(cell, image.Pt(x, p.Y))
+		x += cell.Width()
+	}
+}
+
+// SetBuffer copies the cells of src that overlap the Buffer into it.
+func (self *Buffer) SetBuffer(src *Buffer) {
+	rect := src.Rectangle.Intersect(self.Rectangle)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			p := image.Pt(x, y)
+			c := src.Cells[src.index(p)]
+			// continuation cells are written along with the wide cell before them
+			if c.IsContinuation() && x > rect.Min.X {
+				continue
+			}
+			self.SetCell(c, p)
+		}
+	}
+}
+
+// Range calls f for every cell of the Buffer in row-major order.
+// Iteration stops if f returns false.
+func (self *Buffer) Range(f func(image.Point, Cell) bool) {
+	width := self.Dx()
+	for i, c := range self.Cells {
+		if !f(image.Pt(self.Min.X+i%width, self.Min.Y+i/width), c) {
+			return
+		}
+	}
+}
+
+// CellMap returns a copy of the cells of the Buffer keyed by their position. Writes into the
+// returned map don't change the Buffer.
+//
+// Deprecated: CellMap used to be a field. Use GetCell or Range to read cells, which do not
+// allocate, and SetCell to write them.
+func (self *Buffer) CellMap() map[image.Point]Cell {
+	cellMap := make(map[image.Point]Cell, len(self.Cells))
+	self.Range(func(p image.Point, c Cell) bool {
+		cellMap[p] = c
+		return true
+	})
+	return cellMap
 }
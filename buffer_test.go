@@ -0,0 +1,109 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"testing"
+)
+
+// bufferRow returns the text of row y of buf, with `.` for the continuation cells.
+func bufferRow(buf *Buffer, y int) string {
+	text := ""
+	for x := buf.Min.X; x < buf.Max.X; x++ {
+		cell := buf.GetCell(image.Pt(x, y))
+		if cell.IsContinuation() {
+			text += "."
+		} else {
+			text += cell.String()
+		}
+	}
+	return text
+}
+
+func expectRow(t *testing.T, buf *Buffer, want string) {
+	t.Helper()
+	if got := bufferRow(buf, buf.Min.Y); got != want {
+		t.Errorf("row = %q, want %q", got, want)
+	}
+}
+
+func TestBufferWideCells(t *testing.T) {
+	buf := NewBuffer(image.Rect(0, 0, 6, 1))
+	buf.SetCell(NewCell('世'), image.Pt(1, 0))
+	expectRow(t, buf, " 世.   ")
+	if w := buf.GetCell(image.Pt(1, 0)).Width(); w != 2 {
+		t.Errorf("width = %d, want 2", w)
+	}
+	if w := buf.GetCell(image.Pt(2, 0)).Width(); w != 0 {
+		t.Errorf("continuation width = %d, want 0", w)
+	}
+
+	// overwriting either half of a wide glyph blanks the other one
+	buf.SetCell(NewCell('x'), image.Pt(2, 0))
+	expectRow(t, buf, "  x   ")
+	buf.SetCell(NewCell('世'), image.Pt(3, 0))
+	buf.SetCell(NewCell('y'), image.Pt(3, 0))
+	expectRow(t, buf, "  xy  ")
+
+	// a wide glyph overwritten by another one starting in its second column
+	buf.SetCell(NewCell('世'), image.Pt(0, 0))
+	buf.SetCell(NewCell('界'), image.Pt(1, 0))
+	expectRow(t, buf, " 界.y  ")
+}
+
+func TestBufferWideCellsAtTheEdge(t *testing.T) {
+	buf := NewBuffer(image.Rect(0, 0, 3, 1))
+	buf.SetCell(NewCell('世'), image.Pt(2, 0))
+	expectRow(t, buf, "   ")
+
+	// continuation cells are never set on their own
+	buf.SetCell(NewCell(continuationRune), image.Pt(0, 0))
+	expectRow(t, buf, "   ")
+}
+
+func TestBufferFillSplitsWideCells(t *testing.T) {
+	buf := NewBuffer(image.Rect(0, 0, 6, 1))
+	buf.SetString("世界界", StyleClear, image.Pt(0, 0))
+	expectRow(t, buf, "世.界.界.")
+	buf.Fill(NewCell('-'), image.Rect(1, 0, 3, 1))
+	expectRow(t, buf, " -- 界.")
+
+	buf.Fill(NewCell('世'), image.Rect(0, 0, 5, 1))
+	expectRow(t, buf, "世.世.  ")
+}
+
+func TestBufferSetStringGraphemes(t *testing.T) {
+	buf := NewBuffer(image.Rect(0, 0, 6, 1))
+	buf.SetString("é🇫🇷x", StyleClear, image.Pt(0, 0))
+	expectRow(t, buf, "é🇫🇷.x  ")
+	if c := buf.GetCell(image.Pt(0, 0)); c.Grapheme != "é" || c.Width() != 1 {
+		t.Errorf("cell = %+v, want the grapheme é of width 1", c)
+	}
+}
+
+func TestBufferSetCellsSkipsContinuations(t *testing.T) {
+	src := NewBuffer(image.Rect(0, 0, 4, 1))
+	src.SetString("世ab", StyleClear, image.Pt(0, 0))
+
+	buf := NewBuffer(image.Rect(0, 0, 5, 1))
+	buf.SetCells(src.Cells, image.Pt(1, 0))
+	expectRow(t, buf, " 世.ab")
+}
+
+func TestBufferSetBuffer(t *testing.T) {
+	src := NewBuffer(image.Rect(0, 0, 4, 1))
+	src.SetString("世界", StyleClear, image.Pt(0, 0))
+
+	// the glyph cut by the edge of buf is blanked
+	buf := NewBuffer(image.Rect(1, 0, 5, 1))
+	buf.SetBuffer(src)
+	expectRow(t, buf, " 界. ")
+
+	buf = NewBuffer(image.Rect(0, 0, 4, 1))
+	buf.SetString("abcd", StyleClear, image.Pt(0, 0))
+	buf.SetBuffer(src)
+	expectRow(t, buf, "世.界.")
+}
@@ -30,12 +30,8 @@ func (self *Canvas) Draw(buf *Buffer) {
 	for point, cell := range self.Canvas.GetCells() {
 		if point.In(self.Rectangle) {
 			convertedCell := Cell{
-				cell.Rune,
-				Style{
-					Color(cell.Color),
-					ColorClear,
-					ModifierClear,
-				},
+				Rune:  cell.Rune,
+				Style: NewStyle(Color(cell.Color)),
 			}
 			buf.SetCell(convertedCell, point)
 		}
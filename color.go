@@ -0,0 +1,129 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"os"
+	"strings"
+)
+
+// colorRGBFlag is set on Colors created with NewRGBColor. The red, green and blue components are
+// stored in the lower 24 bits.
+const colorRGBFlag Color = 1 << 24
+
+// NewRGBColor returns a 24-bit true color.
+// It is mapped to the nearest palette color on terminals that don't support true color.
+func NewRGBColor(r, g, b uint8) Color {
+	return colorRGBFlag | Color(r)<<16 | Color(g)<<8 | Color(b)
+}
+
+// IsRGB reports whether the color was created with NewRGBColor.
+func (self Color) IsRGB() bool {
+	return self >= 0 && self&colorRGBFlag != 0
+}
+
+// RGB returns the red, green and blue components of the color.
+// Palette colors return the components of the default xterm palette, and ColorClear returns black.
+func (self Color) RGB() (uint8, uint8, uint8) {
+	switch {
+	case self.IsRGB():
+		return uint8(self >> 16), uint8(self >> 8), uint8(self)
+	case self >= 0 && self < 256:
+		rgb := xtermPalette[self]
+		return rgb[0], rgb[1], rgb[2]
+	}
+	return 0, 0, 0
+}
+
+// ColorMode is the set of colors a terminal can display.
+type ColorMode uint
+
+const (
+	// ColorModeAuto detects the color mode from the environment, see DetectColorMode.
+	ColorModeAuto ColorMode = iota
+	// ColorMode16 supports the 8 basic colors and their bright variants.
+	ColorMode16
+	// ColorMode256 supports the xterm 256 color palette.
+	ColorMode256
+	// ColorModeTrueColor supports 24-bit colors.
+	ColorModeTrueColor
+)
+
+// DetectColorMode guesses the colors supported by the terminal from the COLORTERM and TERM
+// environment variables.
+func DetectColorMode() ColorMode {
+	colorTerm := os.Getenv("COLORTERM")
+	term := os.Getenv("TERM")
+	switch {
+	case colorTerm == "truecolor" || colorTerm == "24bit" || strings.HasSuffix(term, "-direct"):
+		return ColorModeTrueColor
+	case strings.Contains(term, "256color"):
+		return ColorMode256
+	}
+	switch term {
+	case "", "dumb", "ansi", "linux", "cons25", "vt100", "vt102", "vt220", "cygwin":
+		return ColorMode16
+	}
+	return ColorMode256
+}
+
+// Convert maps the color to the nearest color supported by the given mode.
+// ColorClear is never changed.
+func (self Color) Convert(mode ColorMode) Color {
+	if self == ColorClear {
+		return self
+	}
+	switch mode {
+	case ColorMode16:
+		if !self.IsRGB() && self < 16 {
+			return self
+		}
+		return nearestColor(self, 0, 16)
+	case ColorMode256:
+		if !self.IsRGB() {
+			return self
+		}
+		// the first 16 colors are often redefined by terminal themes, so they are not used
+		return nearestColor(self, 16, 256)
+	}
+	return self
+}
+
+// nearestColor returns the palette color in [from, to) closest to c.
+func nearestColor(c Color, from, to Color) Color {
+	r, g, b := c.RGB()
+	nearest := from
+	nearestDistance := -1
+	for i := from; i < to; i++ {
+		pr, pg, pb := i.RGB()
+		dr, dg, db := int(r)-int(pr), int(g)-int(pg), int(b)-int(pb)
+		distance := dr*dr + dg*dg + db*db
+		if nearestDistance < 0 || distance < nearestDistance {
+			nearest, nearestDistance = i, distance
+		}
+	}
+	return nearest
+}
+
+// xtermPalette holds the red, green and blue components of the default xterm 256 color palette.
+var xtermPalette = func() [256][3]uint8 {
+	palette := [256][3]uint8{
+		{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+		{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+		{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+		{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+	}
+	// 6x6x6 color cube
+	levels := [6]uint8{0, 95, 135, 175, 215, 255}
+	for i := 0; i < 216; i++ {
+		palette[16+i] = [3]uint8{levels[i/36], levels[i/6%6], levels[i%6]}
+	}
+	// grayscale ramp
+	for i := 0; i < 24; i++ {
+		level := uint8(8 + 10*i)
+		palette[232+i] = [3]uint8{level, level, level}
+	}
+	return palette
+}()
@@ -0,0 +1,134 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+)
+
+// Container is a Drawable made of other Drawables, like Grid, TabContainer, Layers or Panel.
+// Mouse routing, focus traversal with Focusables and the Scheduler all walk Containers through
+// their Children.
+//
+// A Container lays its children out in its inner rectangle, relative to its top left corner, and
+// clips their drawing to it with DrawChild.
+type Container interface {
+	Drawable
+	// Children returns the visible children in the order they are drawn.
+	Children() []Drawable
+	// GetInnerRect returns the rectangle the children are laid out and drawn in.
+	GetInnerRect() image.Rectangle
+}
+
+// DrawChild draws a child of a Container into buf, clipped to the inner rectangle of the
+// Container.
+func DrawChild(buf *Buffer, container Container, child Drawable) {
+	buf.SetBuffer(drawClipped(child, container.GetInnerRect()))
+}
+
+// drawClipped draws item into a buffer covering the part of it inside clip.
+func drawClipped(item Drawable, clip image.Rectangle) *Buffer {
+	buf := NewBuffer(item.GetRect().Canon().Intersect(clip))
+	item.Lock()
+	item.Draw(buf)
+	item.Unlock()
+	return buf
+}
+
+// panelChild is a child of a Panel with its rectangle relative to the inner rectangle.
+type panelChild struct {
+	item Drawable
+	rect image.Rectangle
+}
+
+// Panel is a Block holding children placed at fixed positions relative to its inner rectangle.
+// They move with the Panel and their drawing is clipped to its inner rectangle. Custom composite
+// widgets can embed a Panel and add their parts to it:
+//
+//	panel := NewPanel()
+//	panel.Add(label, 0, 0, 20, 1)
+//	panel.Add(list, 0, 1, 20, 10)
+//	panel.SetRect(10, 5, 32, 17)
+type Panel struct {
+	Block
+	children []panelChild
+}
+
+func NewPanel() *Panel {
+	return &Panel{
+		Block: *NewBlock(),
+	}
+}
+
+// Add places item at a rectangle relative to the top left corner of the inner rectangle, on top of
+// the other children. Adding a child again moves it.
+func (self *Panel) Add(item Drawable, x1, y1, x2, y2 int) {
+	child := panelChild{
+		item: item,
+		rect: image.Rect(x1, y1, x2, y2),
+	}
+	self.Lock()
+	added := false
+	for i := range self.children {
+		if self.children[i].item == item {
+			self.children[i] = child
+			added = true
+			break
+		}
+	}
+	if !added {
+		self.children = append(self.children, child)
+	}
+	self.layoutChild(child)
+	self.Unlock()
+	self.MarkDirty()
+}
+
+// Remove takes item out of the Panel.
+func (self *Panel) Remove(item Drawable) {
+	self.Lock()
+	removed := false
+	for i := range self.children {
+		if self.children[i].item == item {
+			self.children = append(self.children[:i], self.children[i+1:]...)
+			removed = true
+			break
+		}
+	}
+	self.Unlock()
+	if removed {
+		self.MarkDirty()
+	}
+}
+
+// Children implements the Container interface.
+func (self *Panel) Children() []Drawable {
+	children := make([]Drawable, len(self.children))
+	for i, child := range self.children {
+		children[i] = child.item
+	}
+	return children
+}
+
+// SetRect implements the Drawable interface and moves the children with the Panel.
+func (self *Panel) SetRect(x1, y1, x2, y2 int) {
+	self.Block.SetRect(x1, y1, x2, y2)
+	for _, child := range self.children {
+		self.layoutChild(child)
+	}
+}
+
+func (self *Panel) layoutChild(child panelChild) {
+	r := child.rect.Add(self.Inner.Min)
+	child.item.SetRect(r.Min.X, r.Min.Y, r.Max.X, r.Max.Y)
+}
+
+// Draw implements the Drawable interface.
+func (self *Panel) Draw(buf *Buffer) {
+	self.Block.Draw(buf)
+	for _, child := range self.children {
+		DrawChild(buf, self, child.item)
+	}
+}
@@ -6,6 +6,8 @@ package termui
 
 import (
 	"fmt"
+	"image"
+	"unicode"
 
 	tb "github.com/nsf/termbox-go"
 )
@@ -15,16 +17,25 @@ List of events:
 	mouse events:
 		<MouseLeft> <MouseRight> <MouseMiddle>
 		<MouseWheelUp> <MouseWheelDown>
+		<MouseRelease> <MouseMove>
+	mouse gestures, see MouseGestures:
+		<MouseClick> <MouseDoubleClick>
+		<MouseDragBegin> <MouseDragMove> <MouseDragEnd>
+		<MouseEnter> <MouseLeave>
 	keyboard events:
 		any uppercase or lowercase letter like j or J
 		<C-d> etc
 		<M-d> etc
 		<Up> <Down> <Left> <Right>
-		<Insert> <Delete> <Home> <End> <Previous> <Next>
+		<Insert> <Delete> <Home> <End> <PageUp> <PageDown>
 		<Backspace> <Tab> <Enter> <Escape> <Space>
+		<S-<Tab>>
 		<C-<Space>> etc
+	paste events:
+		<Paste>
 	terminal events:
         <Resize>
+        <Error>
 
     keyboard events that do not work:
         <C-->
@@ -45,8 +56,39 @@ const (
 	KeyboardEvent EventType = iota
 	MouseEvent
 	ResizeEvent
+	// InterruptEvent is returned by Backend.PollEvent after Backend.Interrupt. It is never
+	// delivered to subscribers.
+	InterruptEvent
+	// CustomEvent is the type of the events posted by the application with Post, whose ID and
+	// Payload are up to the application.
+	CustomEvent
+	// PasteEvent is the type of `<Paste>` events, whose Payload is the pasted string.
+	PasteEvent
+	// ErrorEvent is the type of `<Error>` events, sent when the terminal can't be read anymore,
+	// like when the SSH session drops. Its Payload is the error. Applications should call Close
+	// and exit.
+	ErrorEvent
 )
 
+var eventTypeNames = map[EventType]string{
+	KeyboardEvent:  "keyboard",
+	MouseEvent:     "mouse",
+	ResizeEvent:    "resize",
+	InterruptEvent: "interrupt",
+	CustomEvent:    "custom",
+	PasteEvent:     "paste",
+	ErrorEvent:     "error",
+}
+
+func (self EventType) String() string {
+	if name, ok := eventTypeNames[self]; ok {
+		return name
+	}
+	return fmt.Sprintf("EventType(%d)", uint(self))
+}
+
+// Event is an input or terminal event. Keyboard events carry a Key payload, mouse events a Mouse
+// payload, resize events a Resize payload and error events an error.
 type Event struct {
 	Type    EventType
 	ID      string
@@ -58,6 +100,14 @@ type Mouse struct {
 	Drag bool
 	X    int
 	Y    int
+	// Button is the ID of the button of a gesture, like `<MouseLeft>`, see MouseGestures.
+	Button string
+	// Clicks is the number of consecutive clicks of a `<MouseClick>` gesture.
+	Clicks int
+	// Origin is where the drag gestures began.
+	Origin image.Point
+	// Target is the Drawable entered or left by `<MouseEnter>` and `<MouseLeave>` gestures.
+	Target Drawable
 }
 
 // Resize payload.
@@ -66,99 +116,81 @@ type Resize struct {
 	Height int
 }
 
-// PollEvents gets events from termbox, converts them, then sends them to each of its channels.
-func PollEvents() <-chan Event {
-	ch := make(chan Event)
-	go func() {
-		for {
-			ch <- convertTermboxEvent(tb.PollEvent())
-		}
-	}()
-	return ch
-}
-
-var keyboardMap = map[tb.Key]string{
-	tb.KeyF1:         "<F1>",
-	tb.KeyF2:         "<F2>",
-	tb.KeyF3:         "<F3>",
-	tb.KeyF4:         "<F4>",
-	tb.KeyF5:         "<F5>",
-	tb.KeyF6:         "<F6>",
-	tb.KeyF7:         "<F7>",
-	tb.KeyF8:         "<F8>",
-	tb.KeyF9:         "<F9>",
-	tb.KeyF10:        "<F10>",
-	tb.KeyF11:        "<F11>",
-	tb.KeyF12:        "<F12>",
-	tb.KeyInsert:     "<Insert>",
-	tb.KeyDelete:     "<Delete>",
-	tb.KeyHome:       "<Home>",
-	tb.KeyEnd:        "<End>",
-	tb.KeyPgup:       "<PageUp>",
-	tb.KeyPgdn:       "<PageDown>",
-	tb.KeyArrowUp:    "<Up>",
-	tb.KeyArrowDown:  "<Down>",
-	tb.KeyArrowLeft:  "<Left>",
-	tb.KeyArrowRight: "<Right>",
-
-	tb.KeyCtrlSpace:  "<C-<Space>>", // tb.KeyCtrl2 tb.KeyCtrlTilde
-	tb.KeyCtrlA:      "<C-a>",
-	tb.KeyCtrlB:      "<C-b>",
-	tb.KeyCtrlC:      "<C-c>",
-	tb.KeyCtrlD:      "<C-d>",
-	tb.KeyCtrlE:      "<C-e>",
-	tb.KeyCtrlF:      "<C-f>",
-	tb.KeyCtrlG:      "<C-g>",
-	tb.KeyBackspace:  "<C-<Backspace>>", // tb.KeyCtrlH
-	tb.KeyTab:        "<Tab>",           // tb.KeyCtrlI
-	tb.KeyCtrlJ:      "<C-j>",
-	tb.KeyCtrlK:      "<C-k>",
-	tb.KeyCtrlL:      "<C-l>",
-	tb.KeyEnter:      "<Enter>", // tb.KeyCtrlM
-	tb.KeyCtrlN:      "<C-n>",
-	tb.KeyCtrlO:      "<C-o>",
-	tb.KeyCtrlP:      "<C-p>",
-	tb.KeyCtrlQ:      "<C-q>",
-	tb.KeyCtrlR:      "<C-r>",
-	tb.KeyCtrlS:      "<C-s>",
-	tb.KeyCtrlT:      "<C-t>",
-	tb.KeyCtrlU:      "<C-u>",
-	tb.KeyCtrlV:      "<C-v>",
-	tb.KeyCtrlW:      "<C-w>",
-	tb.KeyCtrlX:      "<C-x>",
-	tb.KeyCtrlY:      "<C-y>",
-	tb.KeyCtrlZ:      "<C-z>",
-	tb.KeyEsc:        "<Escape>", // tb.KeyCtrlLsqBracket tb.KeyCtrl3
-	tb.KeyCtrl4:      "<C-4>",    // tb.KeyCtrlBackslash
-	tb.KeyCtrl5:      "<C-5>",    // tb.KeyCtrlRsqBracket
-	tb.KeyCtrl6:      "<C-6>",
-	tb.KeyCtrl7:      "<C-7>", // tb.KeyCtrlSlash tb.KeyCtrlUnderscore
-	tb.KeySpace:      "<Space>",
-	tb.KeyBackspace2: "<Backspace>", // tb.KeyCtrl8:
+var keyboardMap = map[tb.Key]Key{
+	tb.KeyF1:         {Code: KeyF1},
+	tb.KeyF2:         {Code: KeyF2},
+	tb.KeyF3:         {Code: KeyF3},
+	tb.KeyF4:         {Code: KeyF4},
+	tb.KeyF5:         {Code: KeyF5},
+	tb.KeyF6:         {Code: KeyF6},
+	tb.KeyF7:         {Code: KeyF7},
+	tb.KeyF8:         {Code: KeyF8},
+	tb.KeyF9:         {Code: KeyF9},
+	tb.KeyF10:        {Code: KeyF10},
+	tb.KeyF11:        {Code: KeyF11},
+	tb.KeyF12:        {Code: KeyF12},
+	tb.KeyInsert:     {Code: KeyInsert},
+	tb.KeyDelete:     {Code: KeyDelete},
+	tb.KeyHome:       {Code: KeyHome},
+	tb.KeyEnd:        {Code: KeyEnd},
+	tb.KeyPgup:       {Code: KeyPageUp},
+	tb.KeyPgdn:       {Code: KeyPageDown},
+	tb.KeyArrowUp:    {Code: KeyUp},
+	tb.KeyArrowDown:  {Code: KeyDown},
+	tb.KeyArrowLeft:  {Code: KeyLeft},
+	tb.KeyArrowRight: {Code: KeyRight},
+
+	tb.KeyCtrlSpace:  {Code: KeySpace, Modifier: KeyModCtrl}, // tb.KeyCtrl2 tb.KeyCtrlTilde
+	tb.KeyCtrlA:      {Rune: 'a', Modifier: KeyModCtrl},
+	tb.KeyCtrlB:      {Rune: 'b', Modifier: KeyModCtrl},
+	tb.KeyCtrlC:      {Rune: 'c', Modifier: KeyModCtrl},
+	tb.KeyCtrlD:      {Rune: 'd', Modifier: KeyModCtrl},
+	tb.KeyCtrlE:      {Rune: 'e', Modifier: KeyModCtrl},
+	tb.KeyCtrlF:      {Rune: 'f', Modifier: KeyModCtrl},
+	tb.KeyCtrlG:      {Rune: 'g', Modifier: KeyModCtrl},
+	tb.KeyBackspace:  {Code: KeyBackspace, Modifier: KeyModCtrl}, // tb.KeyCtrlH
+	tb.KeyTab:        {Code: KeyTab},                             // tb.KeyCtrlI
+	tb.KeyCtrlJ:      {Rune: 'j', Modifier: KeyModCtrl},
+	tb.KeyCtrlK:      {Rune: 'k', Modifier: KeyModCtrl},
+	tb.KeyCtrlL:      {Rune: 'l', Modifier: KeyModCtrl},
+	tb.KeyEnter:      {Code: KeyEnter}, // tb.KeyCtrlM
+	tb.KeyCtrlN:      {Rune: 'n', Modifier: KeyModCtrl},
+	tb.KeyCtrlO:      {Rune: 'o', Modifier: KeyModCtrl},
+	tb.KeyCtrlP:      {Rune: 'p', Modifier: KeyModCtrl},
+	tb.KeyCtrlQ:      {Rune: 'q', Modifier: KeyModCtrl},
+	tb.KeyCtrlR:      {Rune: 'r', Modifier: KeyModCtrl},
+	tb.KeyCtrlS:      {Rune: 's', Modifier: KeyModCtrl},
+	tb.KeyCtrlT:      {Rune: 't', Modifier: KeyModCtrl},
+	tb.KeyCtrlU:      {Rune: 'u', Modifier: KeyModCtrl},
+	tb.KeyCtrlV:      {Rune: 'v', Modifier: KeyModCtrl},
+	tb.KeyCtrlW:      {Rune: 'w', Modifier: KeyModCtrl},
+	tb.KeyCtrlX:      {Rune: 'x', Modifier: KeyModCtrl},
+	tb.KeyCtrlY:      {Rune: 'y', Modifier: KeyModCtrl},
+	tb.KeyCtrlZ:      {Rune: 'z', Modifier: KeyModCtrl},
+	tb.KeyEsc:        {Code: KeyEscape},                 // tb.KeyCtrlLsqBracket tb.KeyCtrl3
+	tb.KeyCtrl4:      {Rune: '4', Modifier: KeyModCtrl}, // tb.KeyCtrlBackslash
+	tb.KeyCtrl5:      {Rune: '5', Modifier: KeyModCtrl}, // tb.KeyCtrlRsqBracket
+	tb.KeyCtrl6:      {Rune: '6', Modifier: KeyModCtrl},
+	tb.KeyCtrl7:      {Rune: '7', Modifier: KeyModCtrl}, // tb.KeyCtrlSlash tb.KeyCtrlUnderscore
+	tb.KeySpace:      {Code: KeySpace},
+	tb.KeyBackspace2: {Code: KeyBackspace}, // tb.KeyCtrl8:
 }
 
-// convertTermboxKeyboardEvent converts a termbox keyboard event to a more friendly string format.
-// Combines modifiers into the string instead of having them as additional fields in an event.
+// convertTermboxKeyboardEvent converts a termbox keyboard event to a Key payload and its string ID.
 func convertTermboxKeyboardEvent(e tb.Event) Event {
-	ID := "%s"
-	if e.Mod == tb.ModAlt {
-		ID = "<M-%s>"
-	}
-
+	var key Key
 	if e.Ch != 0 {
-		ID = fmt.Sprintf(ID, string(e.Ch))
-	} else {
-		converted, ok := keyboardMap[e.Key]
-		if !ok {
-			converted = ""
+		key = Key{Rune: e.Ch}
+		if unicode.IsUpper(e.Ch) {
+			key.Modifier = KeyModShift
 		}
-		ID = fmt.Sprintf(ID, converted)
+	} else {
+		key = keyboardMap[e.Key]
 	}
-
-	return Event{
-		Type: KeyboardEvent,
-		ID:   ID,
+	if e.Mod == tb.ModAlt {
+		key.Modifier |= KeyModAlt
 	}
+	return NewKeyboardEvent(key)
 }
 
 var mouseButtonMap = map[tb.Key]string{
@@ -176,6 +208,10 @@ func convertTermboxMouseEvent(e tb.Event) Event {
 		converted = "Unknown_Mouse_Button"
 	}
 	Drag := e.Mod == tb.ModMotion
+	// with TermboxBackend.MouseMotion, termbox reports moves without buttons as released buttons
+	if e.Key == tb.MouseRelease && Drag {
+		converted = "<MouseMove>"
+	}
 	return Event{
 		Type: MouseEvent,
 		ID:   converted,
@@ -188,15 +224,21 @@ func convertTermboxMouseEvent(e tb.Event) Event {
 }
 
 // convertTermboxEvent turns a termbox event into a termui event.
-func convertTermboxEvent(e tb.Event) Event {
-	if e.Type == tb.EventError {
-		panic(e.Err)
-	}
+// It reports false for termbox events that have no termui equivalent.
+func convertTermboxEvent(e tb.Event) (Event, bool) {
 	switch e.Type {
 	case tb.EventKey:
-		return convertTermboxKeyboardEvent(e)
+		return convertTermboxKeyboardEvent(e), true
 	case tb.EventMouse:
-		return convertTermboxMouseEvent(e)
+		return convertTermboxMouseEvent(e), true
+	case tb.EventInterrupt:
+		return Event{Type: InterruptEvent}, true
+	case tb.EventError:
+		return Event{
+			Type:    ErrorEvent,
+			ID:      "<Error>",
+			Payload: e.Err,
+		}, true
 	case tb.EventResize:
 		return Event{
 			Type: ResizeEvent,
@@ -205,7 +247,7 @@ func convertTermboxEvent(e tb.Event) Event {
 				Width:  e.Width,
 				Height: e.Height,
 			},
-		}
+		}, true
 	}
-	return Event{}
+	return Event{}, false
 }
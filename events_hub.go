@@ -0,0 +1,331 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"context"
+	"sync"
+)
+
+// subscriptionQueueSize is the number of events a Subscription holds for a subscriber that
+// doesn't keep up. Later events are dropped until the subscriber catches up.
+const subscriptionQueueSize = 1024
+
+// EventFilter selects the events delivered to a Subscription.
+type EventFilter func(Event) bool
+
+// FilterTypes selects the events of any of the given types.
+func FilterTypes(types ...EventType) EventFilter {
+	return func(e Event) bool {
+		for _, t := range types {
+			if e.Type == t {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// FilterIDs selects the events with any of the given IDs.
+func FilterIDs(ids ...string) EventFilter {
+	return func(e Event) bool {
+		for _, id := range ids {
+			if e.ID == id {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Subscription receives the events of the backend and the posted events selected by its filters.
+// It queues them in a goroutine of its own, so that a subscriber that doesn't keep up never delays
+// the others.
+type Subscription struct {
+	sync.Mutex // guards queue and dropped
+	events     chan Event
+	filters    []EventFilter
+	queue      []Event
+	queued     chan struct{} // receives when the queue is no longer empty
+	dropped    int
+	done       chan struct{} // closed on Unsubscribe
+	delivered  chan struct{} // closed when the delivering goroutine returns
+	once       sync.Once
+}
+
+// Events returns the channel the events are delivered to. It is closed on Unsubscribe, when the
+// Subscription's context is done and when the library is closed.
+func (self *Subscription) Events() <-chan Event {
+	return self.events
+}
+
+// Unsubscribe stops the delivery of events and closes the Events channel.
+// Events not yet received are dropped.
+func (self *Subscription) Unsubscribe() {
+	self.once.Do(func() {
+		hub.remove(self)
+		close(self.done)
+		<-self.delivered
+	})
+}
+
+// Dropped returns the number of events dropped because the subscriber didn't keep up.
+func (self *Subscription) Dropped() int {
+	self.Lock()
+	defer self.Unlock()
+	return self.dropped
+}
+
+func (self *Subscription) matches(e Event) bool {
+	for _, filter := range self.filters {
+		if !filter(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// send queues an event for the subscriber, or drops it if the queue is full. It never blocks.
+func (self *Subscription) send(e Event) {
+	self.Lock()
+	if len(self.queue) >= subscriptionQueueSize {
+		self.dropped++
+		self.Unlock()
+		return
+	}
+	self.queue = append(self.queue, e)
+	self.Unlock()
+	select {
+	case self.queued <- struct{}{}:
+	default:
+	}
+}
+
+// deliver sends the queued events to the Events channel until Unsubscribe, then closes it.
+func (self *Subscription) deliver() {
+	defer close(self.delivered)
+	defer close(self.events)
+	for {
+		select {
+		case <-self.queued:
+		case <-self.done:
+			return
+		}
+		for {
+			self.Lock()
+			if len(self.queue) == 0 {
+				self.Unlock()
+				break
+			}
+			e := self.queue[0]
+			self.queue = self.queue[1:]
+			self.Unlock()
+			select {
+			case self.events <- e:
+			case <-self.done:
+				return
+			}
+		}
+	}
+}
+
+// eventHub reads the events of the backend in one goroutine and queues them with the posted
+// events. Another goroutine fans the queued events out to the queues of the subscriptions, so that
+// neither posting nor the other subscriptions wait for a subscriber.
+type eventHub struct {
+	sync.Mutex
+	subscriptions map[*Subscription]struct{}
+	queue         []Event
+	queued        chan struct{} // receives when the queue is no longer empty
+	running       bool
+	done          chan struct{} // closed when the library is closed
+	polling       chan struct{} // closed when the polling goroutine returns
+	publishing    chan struct{} // closed when the publishing goroutine returns
+}
+
+var hub = newEventHub()
+
+func newEventHub() *eventHub {
+	return &eventHub{
+		subscriptions: make(map[*Subscription]struct{}),
+		queued:        make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+}
+
+// Subscribe returns a Subscription receiving the events that match all the filters.
+// Every Subscription receives its own copy of each event, in the order they were read from the
+// backend or posted. Up to 1024 events are queued for a subscriber that doesn't keep up, later
+// ones are dropped, see Subscription.Dropped.
+// The Subscription ends when the context is done, see Subscription.Unsubscribe.
+func Subscribe(ctx context.Context, filters ...EventFilter) *Subscription {
+	sub := &Subscription{
+		events:    make(chan Event),
+		filters:   filters,
+		queued:    make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		delivered: make(chan struct{}),
+	}
+	go sub.deliver()
+	hub.add(sub)
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				sub.Unsubscribe()
+			case <-sub.done:
+			}
+		}()
+	}
+	return sub
+}
+
+// PollEvents returns a channel receiving every event until the library is closed or the channel
+// is passed to UnsubscribeEvents.
+// Each call returns a new channel receiving all the events, see Subscribe.
+func PollEvents() <-chan Event {
+	return Subscribe(context.Background()).Events()
+}
+
+// UnsubscribeEvents ends the Subscription delivering to a channel returned by PollEvents or
+// Subscription.Events, see Subscription.Unsubscribe.
+func UnsubscribeEvents(events <-chan Event) {
+	if sub := hub.subscription(events); sub != nil {
+		sub.Unsubscribe()
+	}
+}
+
+// PostEvent adds an event to the stream delivered to subscribers, after the events already read
+// from the backend or posted. It never blocks.
+func PostEvent(e Event) {
+	hub.enqueue(e)
+}
+
+// Post adds a CustomEvent with the given ID and payload to the stream delivered to subscribers,
+// see PostEvent.
+func Post(id string, payload interface{}) {
+	PostEvent(Event{
+		Type:    CustomEvent,
+		ID:      id,
+		Payload: payload,
+	})
+}
+
+func (self *eventHub) add(sub *Subscription) {
+	self.Lock()
+	defer self.Unlock()
+	self.subscriptions[sub] = struct{}{}
+	if !self.running {
+		self.running = true
+		self.polling = make(chan struct{})
+		self.publishing = make(chan struct{})
+		go self.poll(self.done, self.polling)
+		go self.publish(self.done, self.publishing)
+	}
+}
+
+func (self *eventHub) remove(sub *Subscription) {
+	self.Lock()
+	defer self.Unlock()
+	delete(self.subscriptions, sub)
+}
+
+// subscription returns the Subscription delivering to events, or nil if there is none.
+func (self *eventHub) subscription(events <-chan Event) *Subscription {
+	self.Lock()
+	defer self.Unlock()
+	for sub := range self.subscriptions {
+		if sub.Events() == events {
+			return sub
+		}
+	}
+	return nil
+}
+
+func (self *eventHub) enqueue(e Event) {
+	self.Lock()
+	self.queue = append(self.queue, e)
+	self.Unlock()
+	select {
+	case self.queued <- struct{}{}:
+	default:
+	}
+}
+
+// poll reads events from the backend until it is interrupted by close.
+func (self *eventHub) poll(done, stopped chan struct{}) {
+	defer close(stopped)
+	for {
+		e := backend.PollEvent()
+		select {
+		case <-done:
+			if e.Type == InterruptEvent {
+				return
+			}
+			continue
+		default:
+		}
+		if e.Type != InterruptEvent {
+			self.enqueue(e)
+		}
+	}
+}
+
+// publish queues the events to the matching subscriptions until close.
+func (self *eventHub) publish(done, stopped chan struct{}) {
+	defer close(stopped)
+	for {
+		select {
+		case <-self.queued:
+		case <-done:
+			return
+		}
+		for {
+			self.Lock()
+			if len(self.queue) == 0 {
+				self.Unlock()
+				break
+			}
+			e := self.queue[0]
+			self.queue = self.queue[1:]
+			subscriptions := make([]*Subscription, 0, len(self.subscriptions))
+			for sub := range self.subscriptions {
+				subscriptions = append(subscriptions, sub)
+			}
+			self.Unlock()
+			for _, sub := range subscriptions {
+				if sub.matches(e) {
+					sub.send(e)
+				}
+			}
+		}
+	}
+}
+
+// close stops the goroutines of the hub and ends every Subscription. Queued events are dropped.
+// The hub can be used again after.
+func (self *eventHub) close() {
+	self.Lock()
+	running, polling, publishing := self.running, self.polling, self.publishing
+	close(self.done)
+	self.Unlock()
+
+	if running {
+		backend.Interrupt()
+		<-polling
+		<-publishing
+	}
+
+	self.Lock()
+	subscriptions := self.subscriptions
+	self.subscriptions = make(map[*Subscription]struct{})
+	self.queue = nil
+	self.running = false
+	self.done = make(chan struct{})
+	self.Unlock()
+	for sub := range subscriptions {
+		sub.Unsubscribe()
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// initHeadless installs a HeadlessBackend for the test and closes the library after it.
+func initHeadless(t *testing.T, width, height int) *HeadlessBackend {
+	t.Helper()
+	previous := backend
+	screen := NewHeadlessBackend(width, height)
+	SetBackend(screen)
+	if err := Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		Close()
+		SetBackend(previous)
+	})
+	return screen
+}
+
+// receive returns the next event of a channel, failing the test if none arrives soon.
+func receive(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received")
+	}
+	return Event{}
+}
+
+func TestSubscribeFilters(t *testing.T) {
+	screen := initHeadless(t, 10, 10)
+	all := Subscribe(context.Background())
+	custom := Subscribe(context.Background(), FilterTypes(CustomEvent), FilterIDs("b"))
+
+	screen.InjectEvent(NewKeyboardEvent(Key{Rune: 'q'}))
+	if e := receive(t, all.Events()); e.ID != "q" {
+		t.Errorf("received %q, want q", e.ID)
+	}
+	Post("a", 1)
+	Post("b", 2)
+	for _, want := range []string{"a", "b"} {
+		if e := receive(t, all.Events()); e.ID != want {
+			t.Errorf("received %q, want %q", e.ID, want)
+		}
+	}
+	if e := receive(t, custom.Events()); e.ID != "b" || e.Payload != 2 {
+		t.Errorf("filtered subscription received %v", e)
+	}
+}
+
+func TestSlowSubscriberDoesNotBlockOthers(t *testing.T) {
+	initHeadless(t, 10, 10)
+	slow := Subscribe(context.Background())
+	fast := Subscribe(context.Background())
+
+	// the fast subscriber reads each burst before the next one is posted
+	const burst, posted = subscriptionQueueSize / 2, 2 * subscriptionQueueSize
+	for i := 0; i < posted; i += burst {
+		for j := i; j < i+burst; j++ {
+			Post(fmt.Sprint(j), j)
+		}
+		for j := i; j < i+burst; j++ {
+			if e := receive(t, fast.Events()); e.Payload != j {
+				t.Fatalf("received %v, want event %d", e.Payload, j)
+			}
+		}
+	}
+
+	// the slow subscriber keeps a full queue, and maybe one more event waiting on its channel
+	deadline := time.Now().Add(5 * time.Second)
+	for slow.Dropped() < posted-subscriptionQueueSize-1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if dropped := slow.Dropped(); dropped < posted-subscriptionQueueSize-1 || dropped > posted-subscriptionQueueSize {
+		t.Errorf("slow subscriber dropped %d events", dropped)
+	}
+	if e := receive(t, slow.Events()); e.Payload != 0 {
+		t.Errorf("slow subscriber received %v first, want 0", e.Payload)
+	}
+}
+
+func TestUnsubscribeEvents(t *testing.T) {
+	initHeadless(t, 10, 10)
+	events := PollEvents()
+	UnsubscribeEvents(events)
+	Post("a", nil)
+	if _, ok := <-events; ok {
+		t.Error("channel still open after UnsubscribeEvents")
+	}
+}
+
+func TestSubscriptionEnds(t *testing.T) {
+	initHeadless(t, 10, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	withContext := Subscribe(ctx)
+	cancel()
+	if _, ok := <-withContext.Events(); ok {
+		t.Error("channel still open after the context was canceled")
+	}
+
+	polled := PollEvents()
+	Close()
+	if _, ok := <-polled; ok {
+		t.Error("channel still open after Close")
+	}
+}
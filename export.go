@@ -0,0 +1,219 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image"
+	"io"
+	"strings"
+)
+
+const (
+	exportFontFamily = `"DejaVu Sans Mono", Menlo, Consolas, monospace`
+	// exportFontSize, exportCellWidth and exportCellHeight are in pixels
+	exportFontSize   = 14
+	exportCellWidth  = 8.4
+	exportCellHeight = 17
+)
+
+// DrawBuffer draws the items in order into a new Buffer covering all of them, the same way
+// Render composes them on the terminal.
+func DrawBuffer(items ...Drawable) *Buffer {
+	area := image.Rectangle{}
+	for _, item := range items {
+		area = area.Union(item.GetRect())
+	}
+	buf := NewBuffer(area)
+	for _, item := range items {
+		buf.SetBuffer(drawItem(item))
+	}
+	return buf
+}
+
+// exportRun is a sequence of cells of a row that share the same style.
+type exportRun struct {
+	x       int // column of the first cell, relative to the Buffer
+	columns int
+	cells   []Cell
+	style   Style
+}
+
+// exportRuns splits every row of the Buffer into runs of cells with the same style.
+// Continuation cells are left out of the runs' cells but counted in their columns.
+func exportRuns(buf *Buffer) [][]exportRun {
+	rows := make([][]exportRun, buf.Dy())
+	for y := buf.Min.Y; y < buf.Max.Y; y++ {
+		runs := []exportRun{}
+		for x := buf.Min.X; x < buf.Max.X; x++ {
+			cell := buf.GetCell(image.Pt(x, y))
+			if len(runs) == 0 || runs[len(runs)-1].style != cell.Style {
+				runs = append(runs, exportRun{x: x - buf.Min.X, style: cell.Style})
+			}
+			run := &runs[len(runs)-1]
+			run.columns++
+			if !cell.IsContinuation() {
+				run.cells = append(run.cells, cell)
+			}
+		}
+		rows[y-buf.Min.Y] = runs
+	}
+	return rows
+}
+
+// exportColors returns the colors of a style as CSS colors, resolving ColorClear to the Theme's
+// default colors and applying ModifierReverse and ModifierDim, which only dims the foreground.
+func exportColors(style Style) (string, string, string) {
+	fg, bg, underline := style.Fg, style.Bg, style.UnderlineColor()
+	if fg == ColorClear {
+		fg = Theme.Default.Fg
+	}
+	if fg == ColorClear {
+		fg = ColorWhite
+	}
+	if bg == ColorClear {
+		bg = Theme.Default.Bg
+	}
+	if bg == ColorClear {
+		bg = ColorBlack
+	}
+	if style.Modifier&ModifierReverse != 0 {
+		fg, bg = bg, fg
+	}
+	if style.Modifier&ModifierDim != 0 {
+		fg = blendColors(fg, bg)
+	}
+	if underline == ColorClear {
+		underline = fg
+	}
+	return cssColor(fg), cssColor(bg), cssColor(underline)
+}
+
+// blendColors returns the color halfway between two colors.
+func blendColors(a, b Color) Color {
+	ar, ag, ab := a.RGB()
+	br, bg, bb := b.RGB()
+	return NewRGBColor(uint8((int(ar)+int(br))/2), uint8((int(ag)+int(bg))/2), uint8((int(ab)+int(bb))/2))
+}
+
+func cssColor(c Color) string {
+	r, g, b := c.RGB()
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// exportCSS returns the CSS declarations for the text of a style, except for its colors.
+func exportCSS(style Style, underlineColor string) string {
+	declarations := []string{}
+	if style.Modifier&ModifierBold != 0 {
+		declarations = append(declarations, "font-weight:bold")
+	}
+	if style.Modifier&ModifierItalic != 0 {
+		declarations = append(declarations, "font-style:italic")
+	}
+	if style.Modifier&ModifierBlink != 0 {
+		declarations = append(declarations, "animation:blink 1s steps(1) infinite")
+	}
+	lines := []string{}
+	if style.Modifier&(ModifierUnderline|ModifierDoubleUnderline|ModifierCurlyUnderline) != 0 {
+		lines = append(lines, "underline")
+	}
+	if style.Modifier&ModifierStrikethrough != 0 {
+		lines = append(lines, "line-through")
+	}
+	if len(lines) > 0 {
+		decoration := "text-decoration:" + strings.Join(lines, " ")
+		switch {
+		case style.Modifier&ModifierCurlyUnderline != 0:
+			decoration += " wavy"
+		case style.Modifier&ModifierDoubleUnderline != 0:
+			decoration += " double"
+		}
+		declarations = append(declarations, decoration+" "+underlineColor)
+	}
+	return strings.Join(declarations, ";")
+}
+
+// WriteHTML writes the Buffer as a standalone HTML document.
+// Colors and modifiers are kept, and wide glyphs are given the width of two cells.
+func WriteHTML(w io.Writer, buf *Buffer) error {
+	bw := bufio.NewWriter(w)
+	_, bg, _ := exportColors(StyleClear)
+	fmt.Fprintf(bw, `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>termui</title>
+<style>
+pre { margin: 0; padding: 1ch; background: %s; font-family: %s; font-size: %dpx; line-height: %dpx; }
+.wide { display: inline-block; width: 2ch; }
+@keyframes blink { 50%% { opacity: 0; } }
+</style>
+</head>
+<body>
+<pre>`, bg, exportFontFamily, exportFontSize, exportCellHeight)
+	for _, runs := range exportRuns(buf) {
+		for _, run := range runs {
+			fg, bg, underline := exportColors(run.style)
+			css := fmt.Sprintf("color:%s;background:%s", fg, bg)
+			if extra := exportCSS(run.style, underline); extra != "" {
+				css += ";" + extra
+			}
+			fmt.Fprintf(bw, `<span style="%s">`, css)
+			for _, cell := range run.cells {
+				text := html.EscapeString(cell.String())
+				if cell.Width() > 1 {
+					fmt.Fprintf(bw, `<span class="wide">%s</span>`, text)
+				} else {
+					bw.WriteString(text)
+				}
+			}
+			bw.WriteString("</span>")
+		}
+		bw.WriteString("\n")
+	}
+	bw.WriteString("</pre>\n</body>\n</html>\n")
+	return bw.Flush()
+}
+
+// WriteSVG writes the Buffer as a standalone SVG image.
+// Every run of cells with the same style is stretched to its exact number of columns, so that
+// box-drawing glyphs and wide glyphs stay on the grid whatever font is used.
+func WriteSVG(w io.Writer, buf *Buffer) error {
+	bw := bufio.NewWriter(w)
+	width := float64(buf.Dx()) * exportCellWidth
+	height := buf.Dy() * exportCellHeight
+	_, bg, _ := exportColors(StyleClear)
+	fmt.Fprintf(bw, `<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="%.1f" height="%d" viewBox="0 0 %.1f %d">
+<rect width="100%%" height="100%%" fill="%s"/>
+<g font-family='%s' font-size="%d" xml:space="preserve">
+`, width, height, width, height, bg, exportFontFamily, exportFontSize)
+	for y, runs := range exportRuns(buf) {
+		top := y * exportCellHeight
+		for _, run := range runs {
+			fg, runBg, underline := exportColors(run.style)
+			x := float64(run.x) * exportCellWidth
+			runWidth := float64(run.columns) * exportCellWidth
+			if runBg != bg {
+				fmt.Fprintf(bw, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s"/>`+"\n",
+					x, top, runWidth, exportCellHeight, runBg)
+			}
+			text := CellsToString(run.cells)
+			css := exportCSS(run.style, underline)
+			if strings.TrimSpace(text) == "" && !strings.Contains(css, "text-decoration") {
+				continue
+			}
+			if css != "" {
+				css = fmt.Sprintf(` style="%s"`, css)
+			}
+			fmt.Fprintf(bw, `<text x="%.1f" y="%d" textLength="%.1f" lengthAdjust="spacingAndGlyphs" fill="%s"%s>%s</text>`+"\n",
+				x, top+exportFontSize, runWidth, fg, css, html.EscapeString(text))
+		}
+	}
+	bw.WriteString("</g>\n</svg>\n")
+	return bw.Flush()
+}
@@ -0,0 +1,67 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"bytes"
+	"image"
+	"strings"
+	"testing"
+)
+
+// exportBuffer returns a Buffer with a wide glyph, a box-drawing run, characters to escape and
+// reversed, struck through and dimmed text.
+func exportBuffer() *Buffer {
+	buf := NewBuffer(image.Rect(0, 0, 12, 2))
+	buf.SetString("世┌──┐<&>", NewStyle(ColorRed), image.Pt(0, 0))
+	buf.SetString("rev", NewStyle(ColorClear, ColorClear, ModifierReverse), image.Pt(0, 1))
+	buf.SetString("del", NewStyle(ColorGreen, ColorClear, ModifierStrikethrough), image.Pt(3, 1))
+	buf.SetString("dim", NewStyle(ColorClear, ColorBlue, ModifierDim), image.Pt(6, 1))
+	return buf
+}
+
+func expectContains(t *testing.T, document string, parts ...string) {
+	t.Helper()
+	for _, part := range parts {
+		if !strings.Contains(document, part) {
+			t.Errorf("%s\ndoesn't contain %q", document, part)
+		}
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteHTML(&out, exportBuffer()); err != nil {
+		t.Fatal(err)
+	}
+	document := out.String()
+	expectContains(t, document,
+		`<span style="color:#cd0000;background:#000000"><span class="wide">世</span>┌──┐&lt;&amp;&gt;</span>`,
+		`<span style="color:#000000;background:#e5e5e5">rev</span>`,
+		`<span style="color:#00cd00;background:#000000;text-decoration:line-through #00cd00">del</span>`,
+		// dim blends the foreground with the background, which is kept
+		`<span style="color:#7272e9;background:#0000ee">dim</span>`,
+	)
+	if strings.Contains(document, "opacity:0.5") {
+		t.Error("dimmed text uses opacity, which dims its background too")
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteSVG(&out, exportBuffer()); err != nil {
+		t.Fatal(err)
+	}
+	expectContains(t, out.String(),
+		`width="100.8" height="34"`,
+		// the wide glyph counts as two of the 9 columns of its run
+		`<text x="0.0" y="14" textLength="75.6" lengthAdjust="spacingAndGlyphs" fill="#cd0000">世┌──┐&lt;&amp;&gt;</text>`,
+		`<rect x="0.0" y="17" width="25.2" height="17" fill="#e5e5e5"/>`,
+		`fill="#000000">rev</text>`,
+		`fill="#00cd00" style="text-decoration:line-through #00cd00">del</text>`,
+		`<rect x="50.4" y="17" width="25.2" height="17" fill="#0000ee"/>`,
+		`fill="#7272e9">dim</text>`,
+	)
+}
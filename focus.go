@@ -0,0 +1,240 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"sync"
+)
+
+// Focusable is implemented by widgets that can receive the keyboard focus. Block implements it,
+// drawing its border and title with its focused styles while it has the focus.
+type Focusable interface {
+	Drawable
+	SetFocused(bool)
+	IsFocused() bool
+}
+
+// KeyboardHandler is implemented by widgets that handle keyboard events while they have the focus.
+type KeyboardHandler interface {
+	// HandleKeyboard receives keyboard and paste events and reports whether it handled them.
+	HandleKeyboard(Event) bool
+}
+
+// FocusManager gives the keyboard focus to one widget at a time, cycling through its widgets with
+// NextKeys and PreviousKeys.
+type FocusManager struct {
+	sync.Mutex
+	// NextKeys and PreviousKeys are the IDs of the keys moving the focus to the next and previous
+	// widgets. They default to `<Tab>` and `<S-<Tab>>`.
+	NextKeys     []string
+	PreviousKeys []string
+	// OnChange is called after the focus moves, with the widgets losing and gaining it. Either
+	// can be nil. It can be used to follow the focus with KeyDispatcher.SetFocus.
+	OnChange func(previous, current Focusable)
+
+	widgets []Focusable
+	index   int // index of the focused widget, -1 if none
+}
+
+// NewFocusManager returns a FocusManager cycling through the widgets in order. The first widget
+// gets the focus.
+func NewFocusManager(widgets ...Focusable) *FocusManager {
+	self := &FocusManager{
+		NextKeys:     []string{"<Tab>"},
+		PreviousKeys: []string{"<S-<Tab>>"},
+		index:        -1,
+	}
+	for _, widget := range widgets {
+		self.Add(widget)
+	}
+	return self
+}
+
+// Focusables returns the Focusable widgets among the items and the visible children of their
+// Containers, in the order they are drawn, to build the focus cycle of a whole interface:
+//
+//	focus := NewFocusManager(Focusables(grid)...)
+//
+// Containers are not returned themselves, only their children.
+func Focusables(items ...Drawable) []Focusable {
+	focusables := []Focusable{}
+	for _, item := range items {
+		if container, ok := item.(Container); ok {
+			focusables = append(focusables, Focusables(container.Children()...)...)
+		} else if focusable, ok := item.(Focusable); ok {
+			focusables = append(focusables, focusable)
+		}
+	}
+	return focusables
+}
+
+// Add appends a widget to the focus cycle. It gets the focus if no widget has it.
+func (self *FocusManager) Add(widget Focusable) {
+	self.Lock()
+	self.widgets = append(self.widgets, widget)
+	focusFirst := self.index < 0
+	self.Unlock()
+	if focusFirst {
+		self.SetFocus(widget)
+	} else {
+		widget.SetFocused(false)
+	}
+}
+
+// Remove takes a widget out of the focus cycle. If it had the focus, the next widget gets it.
+func (self *FocusManager) Remove(widget Focusable) {
+	self.Lock()
+	i := self.indexOf(widget)
+	if i < 0 {
+		self.Unlock()
+		return
+	}
+	hadFocus := i == self.index
+	self.widgets = append(self.widgets[:i], self.widgets[i+1:]...)
+	switch {
+	case i < self.index:
+		self.index--
+	case hadFocus && self.index == len(self.widgets):
+		// the last widget had the focus, it wraps around to the first
+		self.index = 0
+		if len(self.widgets) == 0 {
+			self.index = -1
+		}
+	}
+	if !hadFocus {
+		self.Unlock()
+		return
+	}
+	var next Focusable
+	if self.index >= 0 {
+		next = self.widgets[self.index]
+	}
+	onChange := self.OnChange
+	self.Unlock()
+	widget.SetFocused(false)
+	if next != nil {
+		next.SetFocused(true)
+	}
+	if onChange != nil {
+		onChange(widget, next)
+	}
+}
+
+func (self *FocusManager) indexOf(widget Focusable) int {
+	for i, w := range self.widgets {
+		if w == widget {
+			return i
+		}
+	}
+	return -1
+}
+
+// Focused returns the widget with the focus, or nil if there is none.
+func (self *FocusManager) Focused() Focusable {
+	self.Lock()
+	defer self.Unlock()
+	if self.index < 0 {
+		return nil
+	}
+	return self.widgets[self.index]
+}
+
+// SetFocus gives the focus to a widget of the cycle. A nil widget removes the focus.
+func (self *FocusManager) SetFocus(widget Focusable) {
+	self.Lock()
+	i := -1
+	if widget != nil {
+		if i = self.indexOf(widget); i < 0 {
+			self.Unlock()
+			return
+		}
+	}
+	self.focus(i)
+}
+
+// focus moves the focus to the widget at index i and unlocks the FocusManager.
+func (self *FocusManager) focus(i int) {
+	if i == self.index {
+		self.Unlock()
+		return
+	}
+	var previous, current Focusable
+	if self.index >= 0 {
+		previous = self.widgets[self.index]
+	}
+	if i >= 0 {
+		current = self.widgets[i]
+	}
+	self.index = i
+	onChange := self.OnChange
+	self.Unlock()
+
+	if previous != nil {
+		previous.SetFocused(false)
+	}
+	if current != nil {
+		current.SetFocused(true)
+	}
+	if onChange != nil {
+		onChange(previous, current)
+	}
+}
+
+// Next moves the focus to the next widget, wrapping around after the last one.
+func (self *FocusManager) Next() {
+	self.Lock()
+	if len(self.widgets) == 0 {
+		self.Unlock()
+		return
+	}
+	self.focus((self.index + 1) % len(self.widgets))
+}
+
+// Previous moves the focus to the previous widget, wrapping around before the first one.
+func (self *FocusManager) Previous() {
+	self.Lock()
+	if len(self.widgets) == 0 {
+		self.Unlock()
+		return
+	}
+	i := self.index - 1
+	if i < 0 {
+		i = len(self.widgets) - 1
+	}
+	self.focus(i)
+}
+
+// HandleEvent moves the focus on NextKeys and PreviousKeys and delivers the other keyboard and
+// paste events to the focused widget, if it is a KeyboardHandler. Other events are ignored.
+// It reports whether the event was used.
+func (self *FocusManager) HandleEvent(e Event) bool {
+	if e.Type != KeyboardEvent && e.Type != PasteEvent {
+		return false
+	}
+	self.Lock()
+	next, previous := containsID(self.NextKeys, e.ID), containsID(self.PreviousKeys, e.ID)
+	self.Unlock()
+	switch {
+	case e.Type == KeyboardEvent && next:
+		self.Next()
+		return true
+	case e.Type == KeyboardEvent && previous:
+		self.Previous()
+		return true
+	}
+	if handler, ok := self.Focused().(KeyboardHandler); ok {
+		return handler.HandleKeyboard(e)
+	}
+	return false
+}
+
+func containsID(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"time"
+)
+
+// DefaultDoubleClickInterval is the longest time between two presses of a double click.
+const DefaultDoubleClickInterval = 500 * time.Millisecond
+
+// MouseGestures turns raw mouse events into gestures:
+//   - `<MouseClick>` when a button is released without dragging, with the number of consecutive
+//     clicks at the same position in Mouse.Clicks, followed by `<MouseDoubleClick>` on the second
+//   - `<MouseDragBegin>`, `<MouseDragMove>` and `<MouseDragEnd>` when the pointer moves while a
+//     button is pressed, with the position of the press in Mouse.Origin
+//   - `<MouseEnter>` and `<MouseLeave>` when the innermost Drawable under the pointer changes, with
+//     the Drawable in Mouse.Target
+//
+// Hovering without a button pressed is only reported by terminals when TermboxBackend.MouseMotion
+// is set.
+type MouseGestures struct {
+	DoubleClickInterval time.Duration
+
+	pressed   string // button held down, "" if none
+	origin    image.Point
+	dragging  bool
+	clicks    int
+	lastPress time.Time
+	lastClick string // button of the last press
+	lastPoint image.Point
+	hover     Drawable
+}
+
+func NewMouseGestures() *MouseGestures {
+	return &MouseGestures{
+		DoubleClickInterval: DefaultDoubleClickInterval,
+	}
+}
+
+var gestureButtons = map[string]bool{
+	"<MouseLeft>":   true,
+	"<MouseMiddle>": true,
+	"<MouseRight>":  true,
+}
+
+// Feed takes the next raw event and returns the gestures it completes, if any. The items are the
+// Drawables hovered by the pointer, searched like DrawablesAt; without items there are no
+// `<MouseEnter>` and `<MouseLeave>` gestures.
+// Gestures are mouse events and can be delivered with RouteMouseEvent.
+func (self *MouseGestures) Feed(e Event, items ...Drawable) []Event {
+	mouse, ok := e.Payload.(Mouse)
+	if e.Type != MouseEvent || !ok {
+		return nil
+	}
+	p := image.Pt(mouse.X, mouse.Y)
+	gestures := self.hovered(p, items)
+
+	gesture := func(id string, x, y int) {
+		gestures = append(gestures, Event{
+			Type: MouseEvent,
+			ID:   id,
+			Payload: Mouse{
+				X:      x,
+				Y:      y,
+				Button: self.pressed,
+				Clicks: self.clicks,
+				Origin: self.origin,
+			},
+		})
+	}
+
+	switch {
+	case gestureButtons[e.ID] && !mouse.Drag:
+		now := time.Now()
+		if e.ID == self.lastClick && p == self.lastPoint && now.Sub(self.lastPress) <= self.DoubleClickInterval {
+			self.clicks++
+		} else {
+			self.clicks = 1
+		}
+		self.lastPress, self.lastClick, self.lastPoint = now, e.ID, p
+		self.pressed, self.origin, self.dragging = e.ID, p, false
+	case gestureButtons[e.ID] && mouse.Drag:
+		if self.pressed == "" {
+			// the press was missed, like when it happened outside of the terminal
+			self.pressed, self.origin, self.clicks = e.ID, p, 0
+		}
+		if !self.dragging {
+			self.dragging = true
+			self.lastClick = ""
+			gesture("<MouseDragBegin>", self.origin.X, self.origin.Y)
+		}
+		gesture("<MouseDragMove>", p.X, p.Y)
+	case e.ID == "<MouseRelease>" && self.pressed != "":
+		if self.dragging {
+			gesture("<MouseDragEnd>", p.X, p.Y)
+		} else {
+			gesture("<MouseClick>", p.X, p.Y)
+			if self.clicks == 2 {
+				gesture("<MouseDoubleClick>", p.X, p.Y)
+			}
+		}
+		self.pressed, self.dragging = "", false
+	}
+	return gestures
+}
+
+// hovered returns the `<MouseLeave>` and `<MouseEnter>` gestures of moving the pointer to p.
+func (self *MouseGestures) hovered(p image.Point, items []Drawable) []Event {
+	if len(items) == 0 {
+		return nil
+	}
+	gestures := []Event{}
+	target := DrawableAt(p, items...)
+	if target == self.hover {
+		return gestures
+	}
+	if self.hover != nil {
+		gestures = append(gestures, Event{
+			Type:    MouseEvent,
+			ID:      "<MouseLeave>",
+			Payload: Mouse{X: p.X, Y: p.Y, Target: self.hover},
+		})
+	}
+	if target != nil {
+		gestures = append(gestures, Event{
+			Type:    MouseEvent,
+			ID:      "<MouseEnter>",
+			Payload: Mouse{X: p.X, Y: p.Y, Target: target},
+		})
+	}
+	self.hover = target
+	return gestures
+}
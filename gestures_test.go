@@ -0,0 +1,181 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rawMouse returns the raw mouse event of a step like `<MouseLeft> 1,2`, with a `~` after the ID
+// for a drag like `<MouseLeft>~ 1,2`.
+func rawMouse(t *testing.T, step string) Event {
+	var id string
+	var x, y int
+	if _, err := fmt.Sscanf(step, "%s %d,%d", &id, &x, &y); err != nil {
+		t.Fatalf("bad step %q: %v", step, err)
+	}
+	drag := strings.HasSuffix(id, "~")
+	return Event{
+		Type:    MouseEvent,
+		ID:      strings.TrimSuffix(id, "~"),
+		Payload: Mouse{X: x, Y: y, Drag: drag},
+	}
+}
+
+// formatGestures returns the IDs, positions, buttons, click counts and origins of gestures.
+func formatGestures(gestures []Event) string {
+	formatted := []string{}
+	for _, e := range gestures {
+		m := e.Payload.(Mouse)
+		formatted = append(formatted, fmt.Sprintf("%s %d,%d %s clicks:%d origin:%d,%d",
+			e.ID, m.X, m.Y, m.Button, m.Clicks, m.Origin.X, m.Origin.Y))
+	}
+	return strings.Join(formatted, "; ")
+}
+
+func TestMouseGestures(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		steps    []string
+		want     []string // gestures of each step
+	}{
+		{
+			name:  "click",
+			steps: []string{"<MouseLeft> 1,1", "<MouseRelease> 1,1"},
+			want:  []string{"", "<MouseClick> 1,1 <MouseLeft> clicks:1 origin:1,1"},
+		},
+		{
+			name: "double and triple click",
+			steps: []string{
+				"<MouseLeft> 1,1", "<MouseRelease> 1,1",
+				"<MouseLeft> 1,1", "<MouseRelease> 1,1",
+				"<MouseLeft> 1,1", "<MouseRelease> 1,1",
+			},
+			want: []string{
+				"", "<MouseClick> 1,1 <MouseLeft> clicks:1 origin:1,1",
+				"", "<MouseClick> 1,1 <MouseLeft> clicks:2 origin:1,1; <MouseDoubleClick> 1,1 <MouseLeft> clicks:2 origin:1,1",
+				"", "<MouseClick> 1,1 <MouseLeft> clicks:3 origin:1,1",
+			},
+		},
+		{
+			name: "clicks elsewhere",
+			steps: []string{
+				"<MouseLeft> 1,1", "<MouseRelease> 1,1",
+				"<MouseLeft> 2,1", "<MouseRelease> 2,1",
+			},
+			want: []string{
+				"", "<MouseClick> 1,1 <MouseLeft> clicks:1 origin:1,1",
+				"", "<MouseClick> 2,1 <MouseLeft> clicks:1 origin:2,1",
+			},
+		},
+		{
+			name: "clicks with another button",
+			steps: []string{
+				"<MouseLeft> 1,1", "<MouseRelease> 1,1",
+				"<MouseRight> 1,1", "<MouseRelease> 1,1",
+			},
+			want: []string{
+				"", "<MouseClick> 1,1 <MouseLeft> clicks:1 origin:1,1",
+				"", "<MouseClick> 1,1 <MouseRight> clicks:1 origin:1,1",
+			},
+		},
+		{
+			name:     "clicks too far apart",
+			interval: -1,
+			steps: []string{
+				"<MouseLeft> 1,1", "<MouseRelease> 1,1",
+				"<MouseLeft> 1,1", "<MouseRelease> 1,1",
+			},
+			want: []string{
+				"", "<MouseClick> 1,1 <MouseLeft> clicks:1 origin:1,1",
+				"", "<MouseClick> 1,1 <MouseLeft> clicks:1 origin:1,1",
+			},
+		},
+		{
+			name: "drag",
+			steps: []string{
+				"<MouseLeft> 1,1", "<MouseLeft>~ 2,1", "<MouseLeft>~ 3,2", "<MouseRelease> 3,2",
+			},
+			want: []string{
+				"",
+				"<MouseDragBegin> 1,1 <MouseLeft> clicks:1 origin:1,1; <MouseDragMove> 2,1 <MouseLeft> clicks:1 origin:1,1",
+				"<MouseDragMove> 3,2 <MouseLeft> clicks:1 origin:1,1",
+				"<MouseDragEnd> 3,2 <MouseLeft> clicks:1 origin:1,1",
+			},
+		},
+		{
+			name: "drag resets the double click",
+			steps: []string{
+				"<MouseLeft> 1,1", "<MouseLeft>~ 2,1", "<MouseRelease> 2,1",
+				"<MouseLeft> 1,1", "<MouseRelease> 1,1",
+			},
+			want: []string{
+				"",
+				"<MouseDragBegin> 1,1 <MouseLeft> clicks:1 origin:1,1; <MouseDragMove> 2,1 <MouseLeft> clicks:1 origin:1,1",
+				"<MouseDragEnd> 2,1 <MouseLeft> clicks:1 origin:1,1",
+				"", "<MouseClick> 1,1 <MouseLeft> clicks:1 origin:1,1",
+			},
+		},
+		{
+			name:  "missed press",
+			steps: []string{"<MouseLeft>~ 3,3", "<MouseLeft>~ 4,3", "<MouseRelease> 4,3"},
+			want: []string{
+				"<MouseDragBegin> 3,3 <MouseLeft> clicks:0 origin:3,3; <MouseDragMove> 3,3 <MouseLeft> clicks:0 origin:3,3",
+				"<MouseDragMove> 4,3 <MouseLeft> clicks:0 origin:3,3",
+				"<MouseDragEnd> 4,3 <MouseLeft> clicks:0 origin:3,3",
+			},
+		},
+		{
+			name:  "missed release",
+			steps: []string{"<MouseRelease> 1,1", "<MouseWheelUp> 1,1"},
+			want:  []string{"", ""},
+		},
+	}
+	for _, test := range tests {
+		gestures := NewMouseGestures()
+		if test.interval != 0 {
+			gestures.DoubleClickInterval = test.interval
+		}
+		for i, step := range test.steps {
+			if got := formatGestures(gestures.Feed(rawMouse(t, step))); got != test.want[i] {
+				t.Errorf("%s: step %d %q: got %q, want %q", test.name, i, step, got, test.want[i])
+			}
+		}
+	}
+}
+
+func TestMouseGesturesHover(t *testing.T) {
+	left, right := NewBlock(), NewBlock()
+	left.SetRect(0, 0, 5, 5)
+	right.SetRect(5, 0, 10, 5)
+	names := map[Drawable]string{left: "left", right: "right"}
+
+	tests := []struct {
+		step string
+		want string
+	}{
+		{"<MouseMove> 1,1", "<MouseEnter> left"},
+		{"<MouseMove> 2,2", ""},
+		{"<MouseLeft> 6,1", "<MouseLeave> left, <MouseEnter> right"},
+		{"<MouseLeft>~ 20,20", "<MouseLeave> right"},
+		{"<MouseRelease> 20,20", ""},
+	}
+	gestures := NewMouseGestures()
+	for _, test := range tests {
+		hovers := []string{}
+		for _, e := range gestures.Feed(rawMouse(t, test.step), left, right) {
+			if target := e.Payload.(Mouse).Target; target != nil {
+				hovers = append(hovers, e.ID+" "+names[target])
+			}
+		}
+		if got := strings.Join(hovers, ", "); got != test.want {
+			t.Errorf("%q: got %q, want %q", test.step, got, test.want)
+		}
+	}
+}
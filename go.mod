@@ -6,7 +6,7 @@ require (
 	github.com/mattn/go-runewidth v0.0.15
 	github.com/mitchellh/go-wordwrap v1.0.1
 	github.com/nsf/termbox-go v1.1.1
-	github.com/rivo/uniseg v0.4.4 // indirect
+	github.com/rivo/uniseg v0.4.4
 )
 
 replace github.com/sparques/termui/v3 => ./
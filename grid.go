@@ -4,7 +4,9 @@
 
 package termui
 
-import "math"
+import (
+	"image"
+)
 
 type gridItemType uint
 
@@ -16,19 +18,24 @@ const (
 type Grid struct {
 	Block
 	Items []*GridItem
+
+	roots []GridItem // the entries of every call to Set
 }
 
 // GridItem represents either a Row or Column in a grid.
 // Holds sizing information and either an []GridItems or a widget.
 type GridItem struct {
-	Type        gridItemType
+	Type gridItemType
+	// Size is the height of a row or the width of a column.
+	Size Size
+	// XRatio, YRatio, WidthRatio and HeightRatio locate leaves as fractions of the grid. They are
+	// only computed when every size is a ratio and are not used to draw the grid.
 	XRatio      float64
 	YRatio      float64
 	WidthRatio  float64
 	HeightRatio float64
 	Entry       interface{} // Entry.type == GridBufferer if IsLeaf else []GridItem
 	IsLeaf      bool
-	ratio       float64
 }
 
 func NewGrid() *Grid {
@@ -39,33 +46,41 @@ func NewGrid() *Grid {
 	return g
 }
 
-// NewCol takes a height percentage and either a widget or a Row or Column
+// NewCol takes a width ratio and either a widget or Rows and Columns.
+// Ratios are shares of the space left by the Fixed sizes of the other columns.
 func NewCol(ratio float64, i ...interface{}) GridItem {
-	_, ok := i[0].(Drawable)
-	entry := i[0]
-	if !ok {
-		entry = i
-	}
-	return GridItem{
-		Type:   col,
-		Entry:  entry,
-		IsLeaf: ok,
-		ratio:  ratio,
-	}
+	return newGridItem(col, Ratio(ratio), i)
 }
 
-// NewRow takes a width percentage and either a widget or a Row or Column
+// NewRow takes a height ratio and either a widget or Rows and Columns.
+// Ratios are shares of the space left by the Fixed sizes of the other rows.
 func NewRow(ratio float64, i ...interface{}) GridItem {
+	return newGridItem(row, Ratio(ratio), i)
+}
+
+// NewColSize takes a width, like Fixed(30) or Flex(1).Min(10), and either a widget or Rows and
+// Columns.
+func NewColSize(size Size, i ...interface{}) GridItem {
+	return newGridItem(col, size, i)
+}
+
+// NewRowSize takes a height, like Fixed(1) or Percent(25).Max(10), and either a widget or Rows
+// and Columns.
+func NewRowSize(size Size, i ...interface{}) GridItem {
+	return newGridItem(row, size, i)
+}
+
+func newGridItem(t gridItemType, size Size, i []interface{}) GridItem {
 	_, ok := i[0].(Drawable)
-	entry := i[0]
-	if !ok {
-		entry = i
+	var entry interface{} = i
+	if ok {
+		entry = i[0]
 	}
 	return GridItem{
-		Type:   row,
+		Type:   t,
+		Size:   size,
 		Entry:  entry,
 		IsLeaf: ok,
-		ratio:  ratio,
 	}
 }
 
@@ -74,10 +89,11 @@ func NewRow(ratio float64, i ...interface{}) GridItem {
 func (self *Grid) Set(entries ...interface{}) {
 	entry := GridItem{
 		Type:   row,
+		Size:   Ratio(1.0),
 		Entry:  entries,
 		IsLeaf: false,
-		ratio:  1.0,
 	}
+	self.roots = append(self.roots, entry)
 	self.setHelper(entry, 1.0, 1.0)
 }
 
@@ -87,9 +103,9 @@ func (self *Grid) setHelper(item GridItem, parentWidthRatio, parentHeightRatio f
 	switch item.Type {
 	case col:
 		HeightRatio = 1.0
-		WidthRatio = item.ratio
+		WidthRatio = item.Size.ratio()
 	case row:
-		HeightRatio = item.ratio
+		HeightRatio = item.Size.ratio()
 		WidthRatio = 1.0
 	}
 	item.WidthRatio = parentWidthRatio * WidthRatio
@@ -117,13 +133,13 @@ func (self *Grid) setHelper(item GridItem, parentWidthRatio, parentHeightRatio f
 			switch child.Type {
 			case col:
 				cols = true
-				XRatio += child.ratio
+				XRatio += child.Size.ratio()
 				if rows {
 					item.HeightRatio /= 2
 				}
 			case row:
 				rows = true
-				YRatio += child.ratio
+				YRatio += child.Size.ratio()
 				if cols {
 					item.WidthRatio /= 2
 				}
@@ -134,50 +150,79 @@ func (self *Grid) setHelper(item GridItem, parentWidthRatio, parentHeightRatio f
 	}
 }
 
+// Draw implements the Drawable interface. It lays the items out in the inner rectangle, then draws
+// them.
 func (self *Grid) Draw(buf *Buffer) {
-
-	blockWidth := float64(self.Dx()) + 1
-	blockHeight := float64(self.Dy()) + 1
-	blockX := self.Min.X
-	blockY := self.Min.Y
 	// Grid is a special case where we use the full height width if no border is set, but if it is set
 	// we draw things inside the border (and possibly the title)
 	if self.Block.Border {
 		self.Block.Draw(buf)
-		blockWidth = float64(self.Inner.Dx()) + 1
-		blockHeight = float64(self.Inner.Dy()) + 1
-		blockX = self.Inner.Min.X
-		blockY = self.Inner.Min.Y
 	}
-
+	for _, root := range self.roots {
+		layoutGridItem(root, self.GetInnerRect())
+	}
 	for _, item := range self.Items {
-		entry, _ := item.Entry.(Drawable)
-
-		x := int(math.Round(blockWidth*item.XRatio)) + blockX
-		y := int(math.Round(blockHeight*item.YRatio)) + blockY
-		w := int(math.Round(blockWidth * item.WidthRatio))
-		h := int(math.Round(blockHeight * item.HeightRatio))
+		if entry, ok := item.Entry.(Drawable); ok {
+			DrawChild(buf, self, entry)
+		}
+	}
+}
 
-		if self.Border {
-			if x+w > self.Inner.Dx() {
-				w--
-			}
-			if y+h > self.Inner.Dy() {
-				h--
-			}
-		} else {
-			if x+w > self.Dx() {
-				w--
-			}
-			if y+h > self.Dy() {
-				h--
-			}
+// layoutGridItem sets the rectangles of the widgets of item, which covers r. The children of item
+// split r horizontally if they are columns and vertically if they are rows, according to the type
+// of the first one.
+func layoutGridItem(item GridItem, r image.Rectangle) {
+	if item.IsLeaf {
+		if entry, ok := item.Entry.(Drawable); ok {
+			entry.SetRect(r.Min.X, r.Min.Y, r.Max.X, r.Max.Y)
+		}
+		return
+	}
+	children := []GridItem{}
+	for _, child := range InterfaceSlice(item.Entry) {
+		if child, ok := child.(GridItem); ok {
+			children = append(children, child)
+		}
+	}
+	if len(children) == 0 {
+		return
+	}
+	horizontal := children[0].Type == col
+	length := r.Dy()
+	if horizontal {
+		length = r.Dx()
+	}
+	sizes := make([]Size, len(children))
+	for i, child := range children {
+		sizes[i] = child.Size
+	}
+	offset := 0
+	for i, l := range distributeSizes(length, sizes) {
+		childRect := image.Rect(r.Min.X, r.Min.Y+offset, r.Max.X, r.Min.Y+offset+l)
+		if horizontal {
+			childRect = image.Rect(r.Min.X+offset, r.Min.Y, r.Min.X+offset+l, r.Max.Y)
 		}
+		layoutGridItem(children[i], childRect)
+		offset += l
+	}
+}
 
-		entry.SetRect(x, y, x+w, y+h)
+// GetInnerRect implements the Container interface. Without a border, the items cover the whole
+// grid.
+func (self *Grid) GetInnerRect() image.Rectangle {
+	if self.Border {
+		return self.Inner
+	}
+	return self.Rectangle
+}
 
-		entry.Lock()
-		entry.Draw(buf)
-		entry.Unlock()
+// Children returns the widgets of the grid. Their rectangles are set when the grid is drawn.
+func (self *Grid) Children() []Drawable {
+	children := make([]Drawable, 0, len(self.Items))
+	for _, item := range self.Items {
+		if entry, ok := item.Entry.(Drawable); ok {
+			children = append(children, entry)
+		}
 	}
+	return children
 }
@@ -0,0 +1,244 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"math"
+	"sort"
+)
+
+type sizeKind uint
+
+const (
+	sizeRatio sizeKind = iota
+	sizeFixed
+	sizeFlex
+)
+
+// Size is the height of a grid row or the width of a grid column:
+//   - Fixed sizes are a number of cells
+//   - Ratio and Percent sizes are a share of the space left by the Fixed sizes of their siblings
+//   - Flex sizes share the space left by the other sizes in proportion to their weights
+//
+// Min and Max bound any size. Sizes are whole cells: the rounding remainders of ratios go to the
+// rows or columns with the largest fractional parts, the first ones on ties, so that siblings
+// cover their parent without gaps or overlaps when their ratios add up to 1 or there is a Flex
+// size. When the Fixed sizes and minimums don't fit, the last rows or columns shrink first.
+//
+// A sidebar 30 columns wide next to the main view, above a status bar 1 row tall:
+//
+//	grid.Set(
+//		NewRowSize(Flex(1),
+//			NewColSize(Fixed(30), sidebar),
+//			NewColSize(Flex(1), main),
+//		),
+//		NewRowSize(Fixed(1), status),
+//	)
+type Size struct {
+	kind  sizeKind
+	value float64
+	min   int
+	max   int // 0 if unbounded
+}
+
+// Fixed returns a size of n cells.
+func Fixed(n int) Size {
+	return Size{kind: sizeFixed, value: float64(n)}
+}
+
+// Ratio returns a size of a fraction of the space left by Fixed sizes, like the ratios taken by
+// NewRow and NewCol.
+func Ratio(ratio float64) Size {
+	return Size{kind: sizeRatio, value: ratio}
+}
+
+// Percent returns a size of a percentage of the space left by Fixed sizes.
+func Percent(percent float64) Size {
+	return Ratio(percent / 100)
+}
+
+// Flex returns a size taking a share of the space left by the other sizes in proportion to weight.
+func Flex(weight float64) Size {
+	return Size{kind: sizeFlex, value: weight}
+}
+
+// Min returns the size bounded to at least n cells.
+func (self Size) Min(n int) Size {
+	self.min = n
+	return self
+}
+
+// Max returns the size bounded to at most n cells.
+func (self Size) Max(n int) Size {
+	self.max = n
+	return self
+}
+
+// ratio returns the ratio of a Ratio or Percent size, 0 otherwise.
+func (self Size) ratio() float64 {
+	if self.kind != sizeRatio {
+		return 0
+	}
+	return self.value
+}
+
+// clamp bounds n to the minimum and maximum of the size.
+func (self Size) clamp(n int) int {
+	if self.max > 0 && n > self.max {
+		n = self.max
+	}
+	if n < self.min {
+		n = self.min
+	}
+	return MaxInt(n, 0)
+}
+
+// distributeSizes splits length cells between sizes, in order.
+func distributeSizes(length int, sizes []Size) []int {
+	lengths := make([]int, len(sizes))
+
+	// fixed sizes first, ratios are shares of what they leave
+	left := length
+	for i, size := range sizes {
+		if size.kind == sizeFixed {
+			lengths[i] = size.clamp(int(size.value))
+			left -= lengths[i]
+		}
+	}
+	left = MaxInt(left, 0)
+
+	ratios := []int{}
+	exact := make([]float64, len(sizes))
+	for i, size := range sizes {
+		if size.kind == sizeRatio {
+			ratios = append(ratios, i)
+			exact[i] = float64(left) * math.Max(size.value, 0)
+		}
+	}
+	roundShares(lengths, ratios, exact)
+	for _, i := range ratios {
+		lengths[i] = sizes[i].clamp(lengths[i])
+	}
+
+	used := 0
+	for _, l := range lengths {
+		used += l
+	}
+	distributeFlex(lengths, sizes, length-used)
+	shrinkSizes(lengths, sizes, length)
+	return lengths
+}
+
+// roundShares rounds the exact shares of the items at indexes down, then gives the cells left by
+// rounding to the items with the largest fractional parts, the first ones on ties.
+func roundShares(lengths []int, indexes []int, exact []float64) {
+	total := 0.0
+	rounded := 0
+	for _, i := range indexes {
+		// the epsilon keeps shares like 3 * (1.0/3) from being rounded down to 2.999...
+		lengths[i] = int(math.Floor(exact[i] + 1e-9))
+		total += exact[i]
+		rounded += lengths[i]
+	}
+	extra := int(math.Floor(total+1e-9)) - rounded
+	if extra <= 0 {
+		return
+	}
+	byFraction := append([]int{}, indexes...)
+	sort.SliceStable(byFraction, func(a, b int) bool {
+		i, j := byFraction[a], byFraction[b]
+		return exact[i]-float64(lengths[i]) > exact[j]-float64(lengths[j])
+	})
+	for k := 0; k < extra && k < len(byFraction); k++ {
+		lengths[byFraction[k]]++
+	}
+}
+
+// distributeFlex shares the space left between the Flex sizes in proportion to their weights.
+// Sizes whose share is out of their bounds are set to the bound and the rest is shared again
+// between the others.
+func distributeFlex(lengths []int, sizes []Size, left int) {
+	active := []int{}
+	for i, size := range sizes {
+		if size.kind == sizeFlex {
+			active = append(active, i)
+		}
+	}
+	for len(active) > 0 {
+		weights := 0.0
+		for _, i := range active {
+			weights += math.Max(sizes[i].value, 0)
+		}
+		exact := make([]float64, len(sizes))
+		for _, i := range active {
+			if weights > 0 && left > 0 {
+				exact[i] = float64(left) * math.Max(sizes[i].value, 0) / weights
+			}
+		}
+		roundShares(lengths, active, exact)
+
+		// bound the sizes below their minimum first, then the ones above their maximum
+		bounded := []int{}
+		for _, i := range active {
+			if lengths[i] < sizes[i].min {
+				bounded = append(bounded, i)
+			}
+		}
+		if len(bounded) == 0 {
+			for _, i := range active {
+				if sizes[i].max > 0 && lengths[i] > sizes[i].max {
+					bounded = append(bounded, i)
+				}
+			}
+		}
+		if len(bounded) == 0 {
+			return
+		}
+		remaining := []int{}
+		for _, i := range active {
+			if containsInt(bounded, i) {
+				lengths[i] = sizes[i].clamp(lengths[i])
+				left -= lengths[i]
+			} else {
+				remaining = append(remaining, i)
+			}
+		}
+		active = remaining
+	}
+}
+
+// shrinkSizes takes the cells over length back from the last sizes: the ones that aren't Fixed down
+// to their minimum, then any size down to its minimum, then down to nothing.
+func shrinkSizes(lengths []int, sizes []Size, length int) {
+	over := -length
+	for _, l := range lengths {
+		over += l
+	}
+	passes := []func(i int) (bool, int){
+		func(i int) (bool, int) { return sizes[i].kind != sizeFixed, sizes[i].min },
+		func(i int) (bool, int) { return true, sizes[i].min },
+		func(i int) (bool, int) { return true, 0 },
+	}
+	for _, pass := range passes {
+		for i := len(lengths) - 1; i >= 0 && over > 0; i-- {
+			ok, min := pass(i)
+			if !ok || lengths[i] <= min {
+				continue
+			}
+			cut := MinInt(lengths[i]-min, over)
+			lengths[i] -= cut
+			over -= cut
+		}
+	}
+}
+
+func containsInt(ints []int, n int) bool {
+	for _, i := range ints {
+		if i == n {
+			return true
+		}
+	}
+	return false
+}
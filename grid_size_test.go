@@ -0,0 +1,59 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDistributeSizes(t *testing.T) {
+	tests := []struct {
+		name   string
+		length int
+		sizes  []Size
+		want   []int
+	}{
+		{"ratios", 10, []Size{Ratio(0.5), Ratio(0.5)}, []int{5, 5}},
+		{"thirds", 10, []Size{Ratio(1.0 / 3), Ratio(1.0 / 3), Ratio(1.0 / 3)}, []int{4, 3, 3}},
+		{"largest fraction first", 10, []Size{Ratio(0.24), Ratio(0.36), Ratio(0.4)}, []int{2, 4, 4}},
+		{"first on ties", 10, []Size{Ratio(0.25), Ratio(0.35), Ratio(0.4)}, []int{3, 3, 4}},
+		{"percents", 20, []Size{Percent(25), Percent(75)}, []int{5, 15}},
+		{"ratios under 1 leave a gap", 10, []Size{Ratio(0.3), Ratio(0.3)}, []int{3, 3}},
+		{"ratios share what fixed sizes leave", 30, []Size{Fixed(10), Ratio(0.5), Ratio(0.5)}, []int{10, 10, 10}},
+		{"flex fills the rest", 80, []Size{Fixed(30), Flex(1)}, []int{30, 50}},
+		{"flex weights", 10, []Size{Flex(1), Flex(2), Flex(2)}, []int{2, 4, 4}},
+		{"flex after ratios", 10, []Size{Ratio(0.5), Flex(1), Flex(1)}, []int{5, 3, 2}},
+		{"flex minimum", 10, []Size{Flex(1).Min(8), Flex(1)}, []int{8, 2}},
+		{"flex maximum", 10, []Size{Flex(1).Max(2), Flex(1)}, []int{2, 8}},
+		{"ratio bounds", 10, []Size{Ratio(0.1).Min(3), Ratio(0.9).Max(4)}, []int{3, 4}},
+		{"last sizes shrink first", 10, []Size{Fixed(6), Fixed(6)}, []int{6, 4}},
+		{"minimums shrink after the other sizes", 10, []Size{Ratio(0.5), Flex(1).Min(6)}, []int{4, 6}},
+		{"fixed sizes shrink for minimums", 10, []Size{Fixed(6), Flex(1).Min(6)}, []int{4, 6}},
+		{"shrink down to minimums", 10, []Size{Fixed(4).Min(4), Fixed(8).Min(3), Fixed(8).Min(3)}, []int{4, 3, 3}},
+		{"shrink below minimums", 4, []Size{Fixed(3).Min(3), Fixed(3).Min(3)}, []int{3, 1}},
+		{"no space", 0, []Size{Ratio(0.5), Flex(1), Fixed(2)}, []int{0, 0, 0}},
+		{"negative values", 10, []Size{Ratio(-1), Flex(-1), Flex(1)}, []int{0, 0, 10}},
+	}
+	for _, test := range tests {
+		got := distributeSizes(test.length, test.sizes)
+		if fmt.Sprint(got) != fmt.Sprint(test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestDistributeSizesCoverTheLength(t *testing.T) {
+	for length := 0; length < 50; length++ {
+		sizes := []Size{Ratio(1.0 / 7), Ratio(2.0 / 7), Ratio(4.0 / 7)}
+		total := 0
+		for _, l := range distributeSizes(length, sizes) {
+			total += l
+		}
+		if total != length {
+			t.Errorf("length %d: sizes add up to %d", length, total)
+		}
+	}
+}
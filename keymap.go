@@ -0,0 +1,407 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultChordTimeout is how long a KeyDispatcher waits for the next key of a partial chord.
+const DefaultChordTimeout = time.Second
+
+// KeyHandler handles the event that completed a binding.
+type KeyHandler func(Event)
+
+// Keymap binds event IDs and sequences of keyboard event IDs to handlers.
+// A sequence is a space separated list of IDs, like `g g` or `<C-x> <C-s>`.
+type Keymap struct {
+	sync.Mutex
+	// Default, when set, handles the keyboard events that don't start any binding of the Keymap.
+	// They don't reach the Keymaps below it in a KeyDispatcher, which is useful for modes that
+	// take text input.
+	Default KeyHandler
+
+	bindings map[string]KeyHandler
+	prefixes map[string]int // number of bindings starting with each strict prefix
+}
+
+func NewKeymap() *Keymap {
+	return &Keymap{
+		bindings: make(map[string]KeyHandler),
+		prefixes: make(map[string]int),
+	}
+}
+
+// normalizeSequence splits a sequence into its IDs. IDs of keys are rewritten in the form used by
+// keyboard events, so that `<M-<C-a>>` and `<C-<M-a>>` are the same binding.
+func normalizeSequence(sequence string) ([]string, error) {
+	ids := strings.Fields(sequence)
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("empty key sequence")
+	}
+	for i, id := range ids {
+		if key, err := ParseKey(id); err == nil {
+			ids[i] = key.String()
+		}
+	}
+	return ids, nil
+}
+
+// Bind binds a sequence to a handler, replacing any handler already bound to it.
+func (self *Keymap) Bind(sequence string, handler KeyHandler) error {
+	ids, err := normalizeSequence(sequence)
+	if err != nil {
+		return err
+	}
+	self.Lock()
+	defer self.Unlock()
+	joined := strings.Join(ids, " ")
+	if _, ok := self.bindings[joined]; !ok {
+		for i := 1; i < len(ids); i++ {
+			self.prefixes[strings.Join(ids[:i], " ")]++
+		}
+	}
+	self.bindings[joined] = handler
+	return nil
+}
+
+// BindKeys binds a sequence of Keys to a handler.
+func (self *Keymap) BindKeys(handler KeyHandler, keys ...Key) error {
+	ids := make([]string, len(keys))
+	for i, key := range keys {
+		ids[i] = key.String()
+	}
+	return self.Bind(strings.Join(ids, " "), handler)
+}
+
+// Unbind removes the binding of a sequence.
+func (self *Keymap) Unbind(sequence string) {
+	ids, err := normalizeSequence(sequence)
+	if err != nil {
+		return
+	}
+	self.Lock()
+	defer self.Unlock()
+	joined := strings.Join(ids, " ")
+	if _, ok := self.bindings[joined]; !ok {
+		return
+	}
+	delete(self.bindings, joined)
+	for i := 1; i < len(ids); i++ {
+		prefix := strings.Join(ids[:i], " ")
+		if self.prefixes[prefix]--; self.prefixes[prefix] == 0 {
+			delete(self.prefixes, prefix)
+		}
+	}
+}
+
+// lookup returns the handler bound to a sequence and whether longer bindings start with it.
+func (self *Keymap) lookup(ids []string) (KeyHandler, bool) {
+	self.Lock()
+	defer self.Unlock()
+	joined := strings.Join(ids, " ")
+	return self.bindings[joined], self.prefixes[joined] > 0
+}
+
+func (self *Keymap) defaultHandler() KeyHandler {
+	self.Lock()
+	defer self.Unlock()
+	return self.Default
+}
+
+// KeyDispatcher resolves events against layered Keymaps and calls the bound handlers.
+// Keymaps are searched in order of precedence:
+//  1. the Keymap of the focused widget, see SetWidgetKeymap and SetFocus
+//  2. the layers, from the last pushed to the first, see PushLayer
+//  3. the mode, see SetMode
+//  4. the global Keymap
+//
+// A binding shadows the bindings of the same keys in the Keymaps below it, and so does the Default
+// handler of a Keymap for the keys that don't start any of its bindings. While the keys typed so
+// far start a longer binding in the shadowing Keymap or in a Keymap above it, the dispatcher waits
+// for the next key, which continues the chord in the highest of these Keymaps that has a binding
+// for it. A widget binding `g g` thus doesn't hide a global `g x`, and a global `g` is still called
+// when the chord is broken by another key.
+//
+// While a chord is partially typed, the dispatcher waits for its next key for ChordTimeout. When
+// no key arrives in time, FlushChord calls the handler bound to the keys typed so far, if any, and
+// starts over. Since handlers are only called from HandleEvent and FlushChord, a run loop should
+// select on ChordTimer:
+//
+//	for {
+//		select {
+//		case e := <-uiEvents:
+//			keys.HandleEvent(e)
+//		case <-keys.ChordTimer():
+//			keys.FlushChord()
+//		}
+//	}
+type KeyDispatcher struct {
+	sync.Mutex
+	ChordTimeout time.Duration
+
+	global  *Keymap
+	mode    *Keymap
+	layers  []*Keymap
+	widgets map[Drawable]*Keymap
+	focus   Drawable
+
+	pending       []Event
+	continuations []*Keymap  // Keymaps with longer bindings starting with the pending chord
+	handler       KeyHandler // handler bound to the pending chord, nil if none
+	deadline      time.Time
+	timer         *time.Timer
+}
+
+func NewKeyDispatcher(global *Keymap) *KeyDispatcher {
+	if global == nil {
+		global = NewKeymap()
+	}
+	return &KeyDispatcher{
+		ChordTimeout: DefaultChordTimeout,
+		global:       global,
+		widgets:      make(map[Drawable]*Keymap),
+	}
+}
+
+// Global returns the global Keymap.
+func (self *KeyDispatcher) Global() *Keymap {
+	return self.global
+}
+
+// SetMode replaces the mode Keymap, like normal and insert modes of a vi-like editor.
+// A nil Keymap leaves only the global Keymap below the layers. Any pending chord is dropped.
+func (self *KeyDispatcher) SetMode(keymap *Keymap) {
+	self.Lock()
+	defer self.Unlock()
+	self.mode = keymap
+	self.reset()
+}
+
+// Mode returns the mode Keymap.
+func (self *KeyDispatcher) Mode() *Keymap {
+	self.Lock()
+	defer self.Unlock()
+	return self.mode
+}
+
+// PushLayer adds a Keymap taking precedence over the mode and the layers pushed before it.
+func (self *KeyDispatcher) PushLayer(keymap *Keymap) {
+	self.Lock()
+	defer self.Unlock()
+	self.layers = append(self.layers, keymap)
+	self.reset()
+}
+
+// PopLayer removes and returns the last pushed layer, or returns nil if there is none.
+func (self *KeyDispatcher) PopLayer() *Keymap {
+	self.Lock()
+	defer self.Unlock()
+	if len(self.layers) == 0 {
+		return nil
+	}
+	keymap := self.layers[len(self.layers)-1]
+	self.layers = self.layers[:len(self.layers)-1]
+	self.reset()
+	return keymap
+}
+
+// SetWidgetKeymap sets the Keymap used while the widget has the focus. A nil Keymap removes it.
+func (self *KeyDispatcher) SetWidgetKeymap(widget Drawable, keymap *Keymap) {
+	self.Lock()
+	defer self.Unlock()
+	if keymap == nil {
+		delete(self.widgets, widget)
+	} else {
+		self.widgets[widget] = keymap
+	}
+}
+
+// SetFocus sets the widget whose Keymap takes precedence over all others. A nil widget removes
+// the focus.
+func (self *KeyDispatcher) SetFocus(widget Drawable) {
+	self.Lock()
+	defer self.Unlock()
+	if widget != self.focus {
+		self.focus = widget
+		self.reset()
+	}
+}
+
+// keymaps returns the Keymaps in order of precedence.
+func (self *KeyDispatcher) keymaps() []*Keymap {
+	keymaps := []*Keymap{}
+	if self.focus != nil {
+		if keymap, ok := self.widgets[self.focus]; ok {
+			keymaps = append(keymaps, keymap)
+		}
+	}
+	for i := len(self.layers) - 1; i >= 0; i-- {
+		keymaps = append(keymaps, self.layers[i])
+	}
+	if self.mode != nil {
+		keymaps = append(keymaps, self.mode)
+	}
+	return append(keymaps, self.global)
+}
+
+// reset drops the pending chord.
+func (self *KeyDispatcher) reset() {
+	self.pending = nil
+	self.continuations = nil
+	self.handler = nil
+	if self.timer != nil {
+		self.timer.Stop()
+		self.timer = nil
+	}
+}
+
+// ChordTimer returns a channel that receives when the pending chord times out, or nil when no
+// chord is pending.
+func (self *KeyDispatcher) ChordTimer() <-chan time.Time {
+	self.Lock()
+	defer self.Unlock()
+	if self.timer == nil {
+		return nil
+	}
+	return self.timer.C
+}
+
+// FlushChord ends the pending chord, calling the handler bound to the keys typed so far if there
+// is one. It reports whether a handler was called.
+func (self *KeyDispatcher) FlushChord() bool {
+	self.Lock()
+	handler, e := self.flush()
+	self.Unlock()
+	if handler == nil {
+		return false
+	}
+	handler(e)
+	return true
+}
+
+// flush drops the pending chord and returns the handler bound to it and its last event.
+func (self *KeyDispatcher) flush() (KeyHandler, Event) {
+	if len(self.pending) == 0 {
+		return nil, Event{}
+	}
+	handler, e := self.handler, self.pending[len(self.pending)-1]
+	self.reset()
+	return handler, e
+}
+
+// match looks a sequence up in Keymaps given in order of precedence. It returns the Keymaps with
+// longer bindings starting with the sequence, and the handler of the first Keymap binding the
+// sequence or, for a single key, handling it by Default. The Keymaps below that one are shadowed.
+func match(keymaps []*Keymap, ids []string) ([]*Keymap, KeyHandler) {
+	continuations := []*Keymap{}
+	for _, keymap := range keymaps {
+		handler, isPrefix := keymap.lookup(ids)
+		if isPrefix {
+			continuations = append(continuations, keymap)
+		}
+		if handler != nil {
+			return continuations, handler
+		}
+		if !isPrefix && len(ids) == 1 {
+			if handler := keymap.defaultHandler(); handler != nil {
+				return continuations, handler
+			}
+		}
+	}
+	return continuations, nil
+}
+
+func eventIDs(events []Event) []string {
+	ids := make([]string, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+// HandleEvent calls the handler bound to the event, if any, and reports whether the event was
+// used: bound, part of a pending chord, or handled by a Keymap's Default handler.
+// Only keyboard events take part in chords; other events are looked up on their own.
+func (self *KeyDispatcher) HandleEvent(e Event) bool {
+	handlers, events, used := self.resolve(e)
+	for i, handler := range handlers {
+		handler(events[i])
+	}
+	return used
+}
+
+// resolve updates the pending chord with the event. It returns the handlers to call, outside of
+// the lock, with their events, and whether the event was used.
+func (self *KeyDispatcher) resolve(e Event) ([]KeyHandler, []Event, bool) {
+	self.Lock()
+	defer self.Unlock()
+
+	handlers := []KeyHandler{}
+	events := []Event{}
+	if e.Type != KeyboardEvent {
+		for _, keymap := range self.keymaps() {
+			if handler, _ := keymap.lookup([]string{e.ID}); handler != nil {
+				return append(handlers, handler), append(events, e), true
+			}
+		}
+		return nil, nil, false
+	}
+
+	// flushes the pending chord, calling the handler bound to it if any
+	flush := func() {
+		if handler, pendingEvent := self.flush(); handler != nil {
+			handlers = append(handlers, handler)
+			events = append(events, pendingEvent)
+		}
+	}
+
+	// a chord that timed out without the run loop flushing it is flushed now
+	if len(self.pending) > 0 && !time.Now().Before(self.deadline) {
+		flush()
+	}
+
+	if len(self.pending) > 0 {
+		sequence := append(eventIDs(self.pending), e.ID)
+		continuations, handler := match(self.continuations, sequence)
+		switch {
+		case len(continuations) > 0:
+			self.wait(e, continuations, handler)
+			return handlers, events, true
+		case handler != nil:
+			self.reset()
+			return append(handlers, handler), append(events, e), true
+		}
+		// the chord is broken: it ends with the keys typed before the event, and the event
+		// starts over on its own
+		flush()
+	}
+
+	continuations, handler := match(self.keymaps(), []string{e.ID})
+	switch {
+	case len(continuations) > 0:
+		self.wait(e, continuations, handler)
+		return handlers, events, true
+	case handler != nil:
+		return append(handlers, handler), append(events, e), true
+	}
+	return handlers, events, false
+}
+
+// wait adds the event to the pending chord, which can be continued in the given Keymaps and is
+// bound to handler, and restarts the chord timer.
+func (self *KeyDispatcher) wait(e Event, continuations []*Keymap, handler KeyHandler) {
+	self.pending = append(self.pending, e)
+	self.continuations = continuations
+	self.handler = handler
+	self.deadline = time.Now().Add(self.ChordTimeout)
+	if self.timer != nil {
+		self.timer.Stop()
+	}
+	self.timer = time.NewTimer(self.ChordTimeout)
+}
@@ -0,0 +1,160 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// keyTrace binds sequences to handlers recording the name of their Keymap and the sequence.
+type keyTrace struct {
+	t     *testing.T
+	calls []string
+}
+
+func (self *keyTrace) bind(keymap *Keymap, name string, sequences ...string) {
+	for _, sequence := range sequences {
+		call := name + ":" + sequence
+		if err := keymap.Bind(sequence, func(Event) { self.calls = append(self.calls, call) }); err != nil {
+			self.t.Fatal(err)
+		}
+	}
+}
+
+// typeKeys sends the keys of a space separated sequence to the dispatcher.
+func (self *keyTrace) typeKeys(d *KeyDispatcher, sequence string) {
+	for _, id := range strings.Fields(sequence) {
+		key, err := ParseKey(id)
+		if err != nil {
+			self.t.Fatal(err)
+		}
+		d.HandleEvent(NewKeyboardEvent(key))
+	}
+}
+
+func (self *keyTrace) expect(want ...string) {
+	self.t.Helper()
+	if strings.Join(self.calls, ",") != strings.Join(want, ",") {
+		self.t.Errorf("calls = %q, want %q", self.calls, want)
+	}
+	self.calls = nil
+}
+
+// newWidgetDispatcher returns a dispatcher whose focused widget has the given Keymap.
+func newWidgetDispatcher(global, widget *Keymap) *KeyDispatcher {
+	d := NewKeyDispatcher(global)
+	w := NewBlock()
+	d.SetWidgetKeymap(w, widget)
+	d.SetFocus(w)
+	return d
+}
+
+func TestKeyDispatcherChordFallsThrough(t *testing.T) {
+	trace := &keyTrace{t: t}
+	global, widget := NewKeymap(), NewKeymap()
+	trace.bind(global, "global", "g x", "g")
+	trace.bind(widget, "widget", "g g")
+	d := newWidgetDispatcher(global, widget)
+
+	trace.typeKeys(d, "g g")
+	trace.expect("widget:g g")
+	trace.typeKeys(d, "g x")
+	trace.expect("global:g x")
+	trace.typeKeys(d, "g")
+	trace.expect()
+	if !d.FlushChord() {
+		t.Error("FlushChord didn't call the global g")
+	}
+	trace.expect("global:g")
+}
+
+func TestKeyDispatcherBrokenChordCallsShadowedBinding(t *testing.T) {
+	trace := &keyTrace{t: t}
+	global, widget := NewKeymap(), NewKeymap()
+	trace.bind(global, "global", "g", "z")
+	trace.bind(widget, "widget", "g g")
+	d := newWidgetDispatcher(global, widget)
+
+	trace.typeKeys(d, "g z")
+	trace.expect("global:g", "global:z")
+}
+
+func TestKeyDispatcherBindingShadowsLowerChords(t *testing.T) {
+	trace := &keyTrace{t: t}
+	global, widget := NewKeymap(), NewKeymap()
+	trace.bind(global, "global", "g x", "x")
+	trace.bind(widget, "widget", "g")
+	d := newWidgetDispatcher(global, widget)
+
+	trace.typeKeys(d, "g x")
+	trace.expect("widget:g", "global:x")
+}
+
+func TestKeyDispatcherDefaultShadowsLowerKeymaps(t *testing.T) {
+	trace := &keyTrace{t: t}
+	global, mode := NewKeymap(), NewKeymap()
+	trace.bind(global, "global", "q", "g g")
+	trace.bind(mode, "mode", "<Escape>")
+	mode.Default = func(e Event) { trace.calls = append(trace.calls, "default:"+e.ID) }
+	d := NewKeyDispatcher(global)
+	d.SetMode(mode)
+
+	trace.typeKeys(d, "q g <Escape>")
+	trace.expect("default:q", "default:g", "mode:<Escape>")
+}
+
+func TestKeyDispatcherChordTimeout(t *testing.T) {
+	trace := &keyTrace{t: t}
+	global := NewKeymap()
+	trace.bind(global, "global", "g", "g g", "d d")
+	d := NewKeyDispatcher(global)
+	d.ChordTimeout = 10 * time.Millisecond
+
+	if d.ChordTimer() != nil {
+		t.Error("ChordTimer isn't nil without a pending chord")
+	}
+	trace.typeKeys(d, "g")
+	select {
+	case <-d.ChordTimer():
+	case <-time.After(time.Second):
+		t.Fatal("the chord didn't time out")
+	}
+	trace.expect()
+	if !d.FlushChord() {
+		t.Error("FlushChord didn't call g")
+	}
+	trace.expect("global:g")
+	if d.ChordTimer() != nil {
+		t.Error("ChordTimer isn't nil after FlushChord")
+	}
+
+	// a chord without a binding of its own times out without calling anything
+	trace.typeKeys(d, "d")
+	if d.FlushChord() {
+		t.Error("FlushChord called a handler for d")
+	}
+	trace.expect()
+	if d.FlushChord() {
+		t.Error("FlushChord called a handler without a pending chord")
+	}
+}
+
+func TestKeyDispatcherLateKeyStartsOver(t *testing.T) {
+	trace := &keyTrace{t: t}
+	global := NewKeymap()
+	trace.bind(global, "global", "g", "g g")
+	d := NewKeyDispatcher(global)
+	d.ChordTimeout = 10 * time.Millisecond
+
+	// the run loop didn't flush the chord in time, the next key does it
+	trace.typeKeys(d, "g")
+	time.Sleep(20 * time.Millisecond)
+	trace.typeKeys(d, "g")
+	trace.expect("global:g")
+	trace.typeKeys(d, "g")
+	trace.expect("global:g g")
+}
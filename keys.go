@@ -0,0 +1,183 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// KeyCode identifies a key that doesn't produce a character.
+type KeyCode uint
+
+const (
+	// KeyRune is the KeyCode of keys that produce a character, held in Key.Rune.
+	KeyRune KeyCode = iota
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+	KeyInsert
+	KeyDelete
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeySpace
+	KeyBackspace
+	KeyTab
+	KeyEnter
+	KeyEscape
+)
+
+// keyCodeNames are the names of the KeyCodes in event IDs, like `<PageUp>`.
+var keyCodeNames = map[KeyCode]string{
+	KeyF1:        "F1",
+	KeyF2:        "F2",
+	KeyF3:        "F3",
+	KeyF4:        "F4",
+	KeyF5:        "F5",
+	KeyF6:        "F6",
+	KeyF7:        "F7",
+	KeyF8:        "F8",
+	KeyF9:        "F9",
+	KeyF10:       "F10",
+	KeyF11:       "F11",
+	KeyF12:       "F12",
+	KeyInsert:    "Insert",
+	KeyDelete:    "Delete",
+	KeyHome:      "Home",
+	KeyEnd:       "End",
+	KeyPageUp:    "PageUp",
+	KeyPageDown:  "PageDown",
+	KeyUp:        "Up",
+	KeyDown:      "Down",
+	KeyLeft:      "Left",
+	KeyRight:     "Right",
+	KeySpace:     "Space",
+	KeyBackspace: "Backspace",
+	KeyTab:       "Tab",
+	KeyEnter:     "Enter",
+	KeyEscape:    "Escape",
+}
+
+var keyCodeByName = func() map[string]KeyCode {
+	codes := make(map[string]KeyCode, len(keyCodeNames))
+	for code, name := range keyCodeNames {
+		codes[name] = code
+	}
+	return codes
+}()
+
+func (self KeyCode) String() string {
+	if name, ok := keyCodeNames[self]; ok {
+		return name
+	}
+	return fmt.Sprintf("KeyCode(%d)", uint(self))
+}
+
+// KeyModifier is a set of modifier keys held down while a key is pressed.
+type KeyModifier uint
+
+const (
+	KeyModShift KeyModifier = 1 << iota
+	KeyModAlt
+	KeyModCtrl
+)
+
+// keyModifierPrefixes are the prefixes of the modifiers in event IDs, like `<C-d>`.
+var keyModifierPrefixes = map[string]KeyModifier{
+	"S-": KeyModShift,
+	"M-": KeyModAlt,
+	"C-": KeyModCtrl,
+}
+
+// Key is the payload of keyboard events.
+// Terminals don't report Shift on its own: it is only set for upper-case characters, whose Rune
+// is already shifted, and for Shift-Tab.
+type Key struct {
+	Rune     rune // character of the key, 0 unless Code is KeyRune
+	Code     KeyCode
+	Modifier KeyModifier
+}
+
+// NewKeyboardEvent returns the keyboard event of a Key, with the Key as its payload.
+func NewKeyboardEvent(key Key) Event {
+	return Event{
+		Type:    KeyboardEvent,
+		ID:      key.String(),
+		Payload: key,
+	}
+}
+
+// String returns the event ID of the Key, like `j`, `<C-d>`, `<M-<Up>>` or `<C-<Backspace>>`.
+// It returns "" for the zero Key.
+func (self Key) String() string {
+	var id string
+	switch {
+	case self.Code != KeyRune:
+		id = "<" + self.Code.String() + ">"
+	case self.Rune != 0:
+		id = string(self.Rune)
+	default:
+		return ""
+	}
+	if self.Modifier&KeyModCtrl != 0 {
+		id = "<C-" + id + ">"
+	}
+	if self.Modifier&KeyModShift != 0 && !(self.Code == KeyRune && unicode.IsUpper(self.Rune)) {
+		id = "<S-" + id + ">"
+	}
+	if self.Modifier&KeyModAlt != 0 {
+		id = "<M-" + id + ">"
+	}
+	return id
+}
+
+// ParseKey converts an event ID like `J`, `<M-a>`, `<C-d>`, `<Enter>` or `<C-<Backspace>>`
+// into a Key. It is the inverse of Key.String.
+func ParseKey(id string) (Key, error) {
+	if utf8.RuneCountInString(id) == 1 {
+		r, _ := utf8.DecodeRuneInString(id)
+		key := Key{Rune: r}
+		if unicode.IsUpper(r) {
+			key.Modifier = KeyModShift
+		}
+		return key, nil
+	}
+	if len(id) < 3 || !strings.HasPrefix(id, "<") || !strings.HasSuffix(id, ">") {
+		return Key{}, fmt.Errorf("invalid key %q", id)
+	}
+	inner := id[1 : len(id)-1]
+	if code, ok := keyCodeByName[inner]; ok {
+		return Key{Code: code}, nil
+	}
+	if len(inner) < 2 {
+		return Key{}, fmt.Errorf("invalid key %q", id)
+	}
+	if modifier, ok := keyModifierPrefixes[inner[:2]]; ok {
+		key, err := ParseKey(inner[2:])
+		if err != nil {
+			return Key{}, fmt.Errorf("invalid key %q: %v", id, err)
+		}
+		key.Modifier |= modifier
+		return key, nil
+	}
+	return Key{}, fmt.Errorf("invalid key %q", id)
+}
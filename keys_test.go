@@ -0,0 +1,54 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "testing"
+
+func TestKeyRoundTrip(t *testing.T) {
+	keys := []struct {
+		key Key
+		id  string
+	}{
+		{Key{Rune: 'j'}, "j"},
+		{Key{Rune: 'J', Modifier: KeyModShift}, "J"},
+		{Key{Rune: '世'}, "世"},
+		{Key{Rune: '<'}, "<"},
+		{Key{Rune: '>', Modifier: KeyModCtrl}, "<C->>"},
+		{Key{Rune: 'd', Modifier: KeyModCtrl}, "<C-d>"},
+		{Key{Rune: 'D', Modifier: KeyModCtrl | KeyModShift}, "<C-D>"},
+		{Key{Rune: 'a', Modifier: KeyModAlt}, "<M-a>"},
+		{Key{Rune: 'x', Modifier: KeyModAlt | KeyModCtrl}, "<M-<C-x>>"},
+		{Key{Code: KeyEnter}, "<Enter>"},
+		{Key{Code: KeyF12}, "<F12>"},
+		{Key{Code: KeyTab, Modifier: KeyModShift}, "<S-<Tab>>"},
+		{Key{Code: KeyBackspace, Modifier: KeyModCtrl}, "<C-<Backspace>>"},
+		{Key{Code: KeyUp, Modifier: KeyModAlt | KeyModCtrl | KeyModShift}, "<M-<S-<C-<Up>>>>"},
+	}
+	for _, k := range keys {
+		if id := k.key.String(); id != k.id {
+			t.Errorf("%+v: String() = %q, want %q", k.key, id, k.id)
+		}
+		key, err := ParseKey(k.id)
+		if err != nil {
+			t.Errorf("ParseKey(%q): %v", k.id, err)
+		} else if key != k.key {
+			t.Errorf("ParseKey(%q) = %+v, want %+v", k.id, key, k.key)
+		}
+	}
+}
+
+func TestParseKeyErrors(t *testing.T) {
+	for _, id := range []string{"", "jk", "<>", "<Enterr>", "<X-a>", "<C-jk>", "<C-<Nope>>", "<C-a"} {
+		if key, err := ParseKey(id); err == nil {
+			t.Errorf("ParseKey(%q) = %+v, want an error", id, key)
+		}
+	}
+}
+
+func TestKeyStringZero(t *testing.T) {
+	if id := (Key{}).String(); id != "" {
+		t.Errorf("String() = %q, want \"\"", id)
+	}
+}
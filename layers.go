@@ -0,0 +1,244 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"sort"
+)
+
+// Layer is a Drawable in a Layers stack. Changes to its fields after it was added must be made with
+// the Layers locked, and are drawn once the Layers are marked dirty.
+type Layer struct {
+	Item Drawable
+	// Z orders the layers, higher layers are drawn over lower ones. Layers with the same Z are
+	// drawn in the order they were added.
+	Z int
+	// Transparent lets the layers below show through the cells Item didn't draw. Otherwise Item
+	// hides them in its whole rectangle, including the cells it left blank.
+	Transparent bool
+	// Modal dims the layers below with ModifierDim and hides them from mouse hit-testing.
+	Modal bool
+	// Fill makes Item cover the inner rectangle of the Layers, its rectangle is set with the one of
+	// the Layers.
+	Fill bool
+
+	buf *Buffer // Item as it was last drawn
+}
+
+// Layers is a stack of z-ordered Drawables, for popups, overlays and modal windows over the rest
+// of the interface. It draws each layer into a buffer of its own and composes them, only drawing
+// again the layers that are dirty or moved, so items changed without their setters must be marked
+// dirty. When a layer is removed, the region it covered is repainted from the layers below.
+type Layers struct {
+	Block
+	layers []*Layer
+}
+
+func NewLayers() *Layers {
+	l := &Layers{
+		Block: *NewBlock(),
+	}
+	l.Border = false
+	return l
+}
+
+// Add puts item on the stack at the given z, above the layers with the same z, and returns its
+// Layer.
+func (self *Layers) Add(item Drawable, z int) *Layer {
+	layer := &Layer{
+		Item: item,
+		Z:    z,
+	}
+	self.Lock()
+	self.push(layer)
+	self.Unlock()
+	self.MarkDirty()
+	return layer
+}
+
+// Remove takes item off the stack.
+func (self *Layers) Remove(item Drawable) {
+	self.Lock()
+	layer := self.remove(item)
+	self.Unlock()
+	if layer != nil {
+		self.MarkDirty()
+	}
+}
+
+// Layer returns the Layer of item, or nil if it isn't on the stack.
+func (self *Layers) Layer(item Drawable) *Layer {
+	self.Lock()
+	defer self.Unlock()
+	for _, layer := range self.layers {
+		if layer.Item == item {
+			return layer
+		}
+	}
+	return nil
+}
+
+// SetZ moves item to the given z, above the layers with the same z.
+func (self *Layers) SetZ(item Drawable, z int) {
+	self.Lock()
+	layer := self.remove(item)
+	if layer != nil {
+		layer.Z = z
+		self.push(layer)
+	}
+	self.Unlock()
+	if layer != nil {
+		self.MarkDirty()
+	}
+}
+
+// push puts a layer on the stack, above the layers with the same z.
+func (self *Layers) push(layer *Layer) {
+	self.layers = append(self.layers, layer)
+	self.sort()
+}
+
+// remove takes the layer of item off the stack and returns it, or nil if it isn't on the stack.
+func (self *Layers) remove(item Drawable) *Layer {
+	for i, layer := range self.layers {
+		if layer.Item == item {
+			self.layers = append(self.layers[:i], self.layers[i+1:]...)
+			return layer
+		}
+	}
+	return nil
+}
+
+func (self *Layers) sort() {
+	sort.SliceStable(self.layers, func(i, j int) bool {
+		return self.layers[i].Z < self.layers[j].Z
+	})
+}
+
+// modal returns the index of the topmost modal layer, or 0 if there is none.
+func (self *Layers) modal() int {
+	for i := len(self.layers) - 1; i >= 0; i-- {
+		if self.layers[i].Modal {
+			return i
+		}
+	}
+	return 0
+}
+
+// Children implements the Container interface. It returns the items from the topmost modal layer
+// up, since the layers below a modal layer can't be pointed at.
+func (self *Layers) Children() []Drawable {
+	children := []Drawable{}
+	for _, layer := range self.layers[self.modal():] {
+		children = append(children, layer.Item)
+	}
+	return children
+}
+
+// GetInnerRect implements the Container interface. Without a border, the layers cover the whole
+// rectangle.
+func (self *Layers) GetInnerRect() image.Rectangle {
+	if self.Border {
+		return self.Inner
+	}
+	return self.Rectangle
+}
+
+// SetRect implements the Drawable interface, setting the rectangle of the Fill layers too.
+func (self *Layers) SetRect(x1, y1, x2, y2 int) {
+	self.Block.SetRect(x1, y1, x2, y2)
+	inner := self.GetInnerRect()
+	for _, layer := range self.layers {
+		if layer.Fill {
+			layer.Item.SetRect(inner.Min.X, inner.Min.Y, inner.Max.X, inner.Max.Y)
+		}
+	}
+}
+
+// IsDirty reports whether the Layers or any of their items must be drawn again.
+func (self *Layers) IsDirty() bool {
+	if self.Block.IsDirty() {
+		return true
+	}
+	for _, layer := range self.layers {
+		if layer.isDirty() {
+			return true
+		}
+	}
+	return false
+}
+
+// isDirty reports whether the item must be drawn again: Drawables that can't report changes always
+// are.
+func (self *Layer) isDirty() bool {
+	if self.buf == nil || self.buf.Rectangle != self.Item.GetRect().Canon() {
+		return true
+	}
+	dirty := false
+	walkDrawables(self.Item, func(item Drawable) {
+		if conditional, ok := item.(ConditionalDrawable); !ok || conditional.IsDirty() {
+			dirty = true
+		}
+	})
+	return dirty
+}
+
+// draw draws the item into the buffer of the layer if it is dirty.
+func (self *Layer) draw() {
+	if !self.isDirty() {
+		return
+	}
+	self.buf = NewBuffer(self.Item.GetRect())
+	if self.Transparent {
+		// the cells left unknown weren't drawn
+		self.buf.Fill(cellUnknown, self.buf.Rectangle)
+	}
+	self.Item.Lock()
+	self.Item.Draw(self.buf)
+	if conditional, ok := self.Item.(ConditionalDrawable); ok {
+		conditional.Clean()
+	}
+	cleanChildren(self.Item)
+	self.Item.Unlock()
+}
+
+// Draw implements the Drawable interface, composing the layers from the lowest to the highest.
+func (self *Layers) Draw(buf *Buffer) {
+	if self.Border {
+		self.Block.Draw(buf)
+	}
+	modal := self.modal()
+	for i, layer := range self.layers {
+		if i == modal && layer.Modal {
+			dimBuffer(buf, self.GetInnerRect())
+		}
+		layer.draw()
+		composeLayer(buf, layer.buf, self.GetInnerRect(), layer.Transparent)
+	}
+}
+
+// composeLayer draws the cells of a layer inside clip over buf. The cells a transparent layer
+// didn't draw are skipped, letting the cells below show through.
+func composeLayer(buf, layer *Buffer, clip image.Rectangle, transparent bool) {
+	layer.Range(func(p image.Point, cell Cell) bool {
+		if cell.IsContinuation() || !p.In(clip) || (transparent && cell == cellUnknown) {
+			return true
+		}
+		buf.SetCell(cell, p)
+		return true
+	})
+}
+
+// dimBuffer adds ModifierDim to the cells of buf in rect.
+func dimBuffer(buf *Buffer, rect image.Rectangle) {
+	rect = rect.Intersect(buf.Rectangle)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			i := buf.index(image.Pt(x, y))
+			buf.Cells[i].Style.Modifier |= ModifierDim
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"testing"
+	"time"
+)
+
+// label is a Block drawing its text at its top left corner, leaving the rest of it undrawn.
+type label struct {
+	Block
+	text string
+}
+
+func newLabel(text string, x1, y1, x2, y2 int) *label {
+	l := &label{Block: *NewBlock(), text: text}
+	l.Border = false
+	l.SetRect(x1, y1, x2, y2)
+	return l
+}
+
+func (self *label) Draw(buf *Buffer) {
+	buf.SetString(self.text, StyleClear, self.Min)
+}
+
+// drawLayers draws layers over a buffer of their size and returns the text of its first row,
+// with the dimmed cells.
+func drawLayers(layers *Layers) (string, []bool) {
+	buf := NewBuffer(layers.GetRect())
+	layers.Draw(buf)
+	text, dimmed := "", []bool{}
+	for x := buf.Min.X; x < buf.Max.X; x++ {
+		cell := buf.GetCell(image.Pt(x, buf.Min.Y))
+		text += cell.String()
+		dimmed = append(dimmed, cell.Style.Modifier&ModifierDim != 0)
+	}
+	return text, dimmed
+}
+
+func TestLayersHideTheLayersBelow(t *testing.T) {
+	for _, modal := range []bool{false, true} {
+		layers := NewLayers()
+		layers.Add(newLabel("background", 0, 0, 0, 0), 0).Fill = true
+		layers.Add(newLabel("ok", 2, 0, 8, 1), 1).Modal = modal
+		layers.SetRect(0, 0, 10, 1)
+
+		text, dimmed := drawLayers(layers)
+		if text != "baok    nd" {
+			t.Errorf("modal %v: got %q, want %q", modal, text, "baok    nd")
+		}
+		for x, dim := range dimmed {
+			inside := x >= 2 && x < 8
+			if dim != (modal && !inside) {
+				t.Errorf("modal %v: cell %d dimmed %v", modal, x, dim)
+			}
+		}
+	}
+}
+
+func TestTransparentLayersOnlyHideTheirCells(t *testing.T) {
+	layers := NewLayers()
+	layers.Add(newLabel("background", 0, 0, 0, 0), 0).Fill = true
+	layers.Add(newLabel("ok", 2, 0, 8, 1), 1).Transparent = true
+	layers.SetRect(0, 0, 10, 1)
+
+	if text, _ := drawLayers(layers); text != "baokground" {
+		t.Errorf("got %q, want %q", text, "baokground")
+	}
+}
+
+func TestRemovedLayersRepaintTheLayersBelow(t *testing.T) {
+	layers := NewLayers()
+	layers.Add(newLabel("background", 0, 0, 0, 0), 0).Fill = true
+	popup := newLabel("ok", 2, 0, 8, 1)
+	layers.Add(popup, 1)
+	layers.SetRect(0, 0, 10, 1)
+	drawLayers(layers)
+
+	layers.Remove(popup)
+	if !layers.IsDirty() {
+		t.Errorf("layers not dirty after removing a layer")
+	}
+	if text, _ := drawLayers(layers); text != "background" {
+		t.Errorf("got %q, want %q", text, "background")
+	}
+}
+
+func TestLayersChangedFromOtherGoroutines(t *testing.T) {
+	screen := initHeadless(t, 10, 1)
+	layers := NewLayers()
+	layers.SetRect(0, 0, 10, 1)
+	runScheduler(t, layers)
+
+	labels := []*label{}
+	for x := 0; x < 10; x++ {
+		l := newLabel("#", x, 0, x+1, 1)
+		layers.Add(l, x)
+		labels = append(labels, l)
+		time.Sleep(100 * time.Microsecond)
+	}
+	waitScreen(t, screen, 0, "##########")
+	for _, l := range labels[:5] {
+		layers.SetZ(l, -1)
+		layers.Remove(l)
+		time.Sleep(100 * time.Microsecond)
+	}
+	waitScreen(t, screen, 0, "     #####")
+}
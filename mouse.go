@@ -0,0 +1,77 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+)
+
+// MouseHandler is implemented by widgets that handle the mouse events under them.
+type MouseHandler interface {
+	// HandleMouse receives a mouse event whose Mouse payload, including the Origin of drag
+	// gestures, is relative to the top left corner of the widget, and reports whether it handled
+	// it. Unhandled events go to the Container of the widget.
+	HandleMouse(Event) bool
+}
+
+// DrawablesAt returns the Drawables under a point, from the topmost item to its innermost child.
+// Items are searched from the last to the first, since later items are drawn over earlier ones,
+// and children of Containers the same way, within the inner rectangle their drawing is clipped to.
+// Rectangles of children are the ones set when their Container was last laid out.
+func DrawablesAt(p image.Point, items ...Drawable) []Drawable {
+	for i := len(items) - 1; i >= 0; i-- {
+		item := items[i]
+		if !p.In(item.GetRect()) {
+			continue
+		}
+		path := []Drawable{item}
+		if container, ok := item.(Container); ok && p.In(container.GetInnerRect()) {
+			path = append(path, DrawablesAt(p, container.Children()...)...)
+		}
+		return path
+	}
+	return nil
+}
+
+// DrawableAt returns the innermost Drawable under a point, or nil if there is none.
+func DrawableAt(p image.Point, items ...Drawable) Drawable {
+	path := DrawablesAt(p, items...)
+	if len(path) == 0 {
+		return nil
+	}
+	return path[len(path)-1]
+}
+
+// RouteMouseEvent delivers a mouse event to the innermost MouseHandler under the pointer, among
+// the items and their children. If it doesn't handle the event, its Containers are tried in turn.
+// Events with a Mouse.Target, like `<MouseLeave>`, are only delivered to their Target.
+// It reports whether a MouseHandler handled the event.
+func RouteMouseEvent(e Event, items ...Drawable) bool {
+	mouse, ok := e.Payload.(Mouse)
+	if e.Type != MouseEvent || !ok {
+		return false
+	}
+	path := []Drawable{mouse.Target}
+	if mouse.Target == nil {
+		path = DrawablesAt(image.Pt(mouse.X, mouse.Y), items...)
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		handler, ok := path[i].(MouseHandler)
+		if !ok {
+			continue
+		}
+		local := mouse
+		min := path[i].GetRect().Min
+		local.X -= min.X
+		local.Y -= min.Y
+		local.Origin = local.Origin.Sub(min)
+		localEvent := e
+		localEvent.Payload = local
+		if handler.HandleMouse(localEvent) {
+			return true
+		}
+	}
+	return false
+}
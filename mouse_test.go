@@ -0,0 +1,136 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"testing"
+)
+
+// mouseTrace records the mouse events delivered to handlers, in widget-local coordinates.
+type mouseTrace struct {
+	calls []string
+}
+
+// mouseBlock is a Block handling the mouse events if handles is set.
+type mouseBlock struct {
+	Block
+	name    string
+	handles bool
+	trace   *mouseTrace
+}
+
+func (self *mouseBlock) HandleMouse(e Event) bool {
+	m := e.Payload.(Mouse)
+	self.trace.calls = append(self.trace.calls, fmt.Sprintf("%s %s %d,%d origin:%d,%d",
+		self.name, e.ID, m.X, m.Y, m.Origin.X, m.Origin.Y))
+	return self.handles
+}
+
+// mousePanel is a Panel handling the mouse events its children don't.
+type mousePanel struct {
+	*Panel
+	trace *mouseTrace
+}
+
+func (self *mousePanel) HandleMouse(e Event) bool {
+	m := e.Payload.(Mouse)
+	self.trace.calls = append(self.trace.calls, fmt.Sprintf("panel %s %d,%d", e.ID, m.X, m.Y))
+	return true
+}
+
+// newMouseScene returns a Panel at (0, 0, 10, 10) with a child at (1, 1, 4, 4), and a block at
+// (5, 5, 15, 15) over the Panel.
+func newMouseScene(trace *mouseTrace, handles bool) (*mousePanel, *mouseBlock, *mouseBlock) {
+	panel := &mousePanel{Panel: NewPanel(), trace: trace}
+	panel.SetRect(0, 0, 10, 10)
+	child := &mouseBlock{Block: *NewBlock(), name: "child", handles: handles, trace: trace}
+	panel.Add(child, 0, 0, 3, 3)
+	over := &mouseBlock{Block: *NewBlock(), name: "over", handles: true, trace: trace}
+	over.SetRect(5, 5, 15, 15)
+	return panel, child, over
+}
+
+func TestDrawablesAt(t *testing.T) {
+	panel, child, over := newMouseScene(&mouseTrace{}, true)
+	// a child reaching out of the inner rectangle of the Panel, which clips it
+	wide := NewBlock()
+	panel.Add(wide, 5, 0, 20, 3)
+	names := map[Drawable]string{panel: "panel", child: "child", over: "over", wide: "wide"}
+
+	tests := []struct {
+		p    image.Point
+		want string
+	}{
+		{image.Pt(2, 2), "panel child"},
+		{image.Pt(0, 0), "panel"},
+		{image.Pt(6, 6), "over"},
+		{image.Pt(7, 2), "panel wide"},
+		{image.Pt(9, 2), "panel"},
+		{image.Pt(12, 2), ""},
+		{image.Pt(20, 20), ""},
+	}
+	for _, test := range tests {
+		path := []string{}
+		for _, d := range DrawablesAt(test.p, panel, over) {
+			path = append(path, names[d])
+		}
+		if got := strings.Join(path, " "); got != test.want {
+			t.Errorf("DrawablesAt(%v) = %q, want %q", test.p, got, test.want)
+		}
+	}
+	if d := DrawableAt(image.Pt(2, 2), panel, over); d != child {
+		t.Errorf("DrawableAt = %v, want the child", d)
+	}
+	if d := DrawableAt(image.Pt(20, 20), panel, over); d != nil {
+		t.Errorf("DrawableAt = %v, want nil", d)
+	}
+}
+
+func TestRouteMouseEvent(t *testing.T) {
+	drag := Event{
+		Type:    MouseEvent,
+		ID:      "<MouseDragMove>",
+		Payload: Mouse{X: 2, Y: 3, Origin: image.Pt(1, 1)},
+	}
+	tests := []struct {
+		name     string
+		handles  bool
+		e        Event
+		want     string
+		returned bool
+	}{
+		{"child", true, drag, "child <MouseDragMove> 1,2 origin:0,0", true},
+		{"unhandled by the child", false, drag, "child <MouseDragMove> 1,2 origin:0,0, panel <MouseDragMove> 2,3", true},
+		{"over the panel", true, Event{Type: MouseEvent, ID: "<MouseLeft>", Payload: Mouse{X: 6, Y: 7}},
+			"over <MouseLeft> 1,2 origin:-5,-5", true},
+		{"nothing under the pointer", true, Event{Type: MouseEvent, ID: "<MouseLeft>", Payload: Mouse{X: 20, Y: 20}},
+			"", false},
+		{"not a mouse event", true, Event{Type: KeyboardEvent, ID: "q"}, "", false},
+	}
+	for _, test := range tests {
+		trace := &mouseTrace{}
+		panel, _, over := newMouseScene(trace, test.handles)
+		returned := RouteMouseEvent(test.e, panel, over)
+		if got := strings.Join(trace.calls, ", "); got != test.want || returned != test.returned {
+			t.Errorf("%s: got %q and %v, want %q and %v", test.name, got, returned, test.want, test.returned)
+		}
+	}
+}
+
+func TestRouteMouseEventToTarget(t *testing.T) {
+	trace := &mouseTrace{}
+	panel, child, over := newMouseScene(trace, true)
+	// the pointer left the child for the block over the panel
+	leave := Event{Type: MouseEvent, ID: "<MouseLeave>", Payload: Mouse{X: 6, Y: 6, Target: child}}
+	if !RouteMouseEvent(leave, panel, over) {
+		t.Error("the target didn't handle the event")
+	}
+	if got, want := strings.Join(trace.calls, ", "), "child <MouseLeave> 5,5 origin:-1,-1"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,365 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// recordedEvent is the JSON form of an Event, one per line of a recording:
+//
+//	{"time":"2019-07-15T10:00:00.5Z","type":"keyboard","id":"<C-d>","key":{"rune":"d","modifier":4}}
+//	{"time":"2019-07-15T10:00:01Z","type":"mouse","id":"<MouseLeft>","mouse":{"x":3,"y":4}}
+//
+// The Key of keyboard events recorded without one is parsed back from their ID.
+type recordedEvent struct {
+	Time    time.Time        `json:"time"`
+	Type    string           `json:"type"`
+	ID      string           `json:"id"`
+	Key     *recordedKey     `json:"key,omitempty"`
+	Mouse   *recordedMouse   `json:"mouse,omitempty"`
+	Resize  *recordedResize  `json:"resize,omitempty"`
+	Text    string           `json:"text,omitempty"`
+	Payload *json.RawMessage `json:"payload,omitempty"`
+}
+
+// recordedKey is the JSON form of a Key payload. Code is the name of the KeyCode, as in event IDs.
+type recordedKey struct {
+	Rune     string      `json:"rune,omitempty"`
+	Code     string      `json:"code,omitempty"`
+	Modifier KeyModifier `json:"modifier,omitempty"`
+}
+
+// recordedMouse is the JSON form of a Mouse payload. Mouse.Target can't be recorded.
+type recordedMouse struct {
+	X      int          `json:"x"`
+	Y      int          `json:"y"`
+	Drag   bool         `json:"drag,omitempty"`
+	Button string       `json:"button,omitempty"`
+	Clicks int          `json:"clicks,omitempty"`
+	Origin *image.Point `json:"origin,omitempty"`
+}
+
+type recordedResize struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// Recorder writes events as JSON lines with the time they were recorded at, to be replayed with
+// ReplayBackend.
+type Recorder struct {
+	sync.Mutex
+	w *bufio.Writer
+}
+
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{
+		w: bufio.NewWriter(w),
+	}
+}
+
+// Record writes an event. Payloads of custom events are written as JSON.
+func (self *Recorder) Record(e Event) error {
+	recorded := recordedEvent{
+		Time: time.Now(),
+		Type: e.Type.String(),
+		ID:   e.ID,
+	}
+	switch payload := e.Payload.(type) {
+	case Key:
+		recorded.Key = &recordedKey{
+			Modifier: payload.Modifier,
+		}
+		if payload.Code == KeyRune {
+			recorded.Key.Rune = string(payload.Rune)
+		} else {
+			recorded.Key.Code = payload.Code.String()
+		}
+	case Mouse:
+		recorded.Mouse = &recordedMouse{
+			X:      payload.X,
+			Y:      payload.Y,
+			Drag:   payload.Drag,
+			Button: payload.Button,
+			Clicks: payload.Clicks,
+		}
+		if payload.Origin != (image.Point{}) {
+			recorded.Mouse.Origin = &payload.Origin
+		}
+	case Resize:
+		recorded.Resize = &recordedResize{
+			Width:  payload.Width,
+			Height: payload.Height,
+		}
+	case string:
+		if e.Type == PasteEvent {
+			recorded.Text = payload
+		}
+	case error:
+		recorded.Text = payload.Error()
+	}
+	if e.Type == CustomEvent && e.Payload != nil {
+		data, err := json.Marshal(e.Payload)
+		if err != nil {
+			return fmt.Errorf("recording %s event %q: %v", e.Type, e.ID, err)
+		}
+		raw := json.RawMessage(data)
+		recorded.Payload = &raw
+	}
+
+	self.Lock()
+	defer self.Unlock()
+	encoder := json.NewEncoder(self.w)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(recorded); err != nil {
+		return err
+	}
+	return self.w.Flush()
+}
+
+// RecordEvents writes the events matching the filters, see Subscribe, until the context is done or
+// the library is closed. It returns the first error writing them.
+func RecordEvents(ctx context.Context, w io.Writer, filters ...EventFilter) error {
+	recorder := NewRecorder(w)
+	sub := Subscribe(ctx, filters...)
+	for e := range sub.Events() {
+		if err := recorder.Record(e); err != nil {
+			sub.Unsubscribe()
+			return err
+		}
+	}
+	return nil
+}
+
+var eventTypeByName = func() map[string]EventType {
+	types := make(map[string]EventType, len(eventTypeNames))
+	for t, name := range eventTypeNames {
+		types[name] = t
+	}
+	return types
+}()
+
+// event converts a recorded event back into an Event. Payloads of custom events are decoded into
+// the types used by encoding/json for interface{} values.
+func (self recordedEvent) event() (Event, error) {
+	t, ok := eventTypeByName[self.Type]
+	if !ok {
+		return Event{}, fmt.Errorf("unknown event type %q", self.Type)
+	}
+	e := Event{
+		Type: t,
+		ID:   self.ID,
+	}
+	switch {
+	case t == KeyboardEvent:
+		key, err := self.key()
+		if err != nil {
+			return Event{}, err
+		}
+		e.Payload = key
+	case t == PasteEvent:
+		e.Payload = self.Text
+	case t == ErrorEvent:
+		e.Payload = errors.New(self.Text)
+	case self.Mouse != nil:
+		mouse := Mouse{
+			X:      self.Mouse.X,
+			Y:      self.Mouse.Y,
+			Drag:   self.Mouse.Drag,
+			Button: self.Mouse.Button,
+			Clicks: self.Mouse.Clicks,
+		}
+		if self.Mouse.Origin != nil {
+			mouse.Origin = *self.Mouse.Origin
+		}
+		e.Payload = mouse
+	case self.Resize != nil:
+		e.Payload = Resize{
+			Width:  self.Resize.Width,
+			Height: self.Resize.Height,
+		}
+	case self.Payload != nil:
+		var payload interface{}
+		if err := json.Unmarshal(*self.Payload, &payload); err != nil {
+			return Event{}, err
+		}
+		e.Payload = payload
+	}
+	return e, nil
+}
+
+// key returns the Key of a recorded keyboard event, parsed from its ID if it was recorded without
+// one.
+func (self recordedEvent) key() (Key, error) {
+	if self.Key == nil {
+		return ParseKey(self.ID)
+	}
+	key := Key{
+		Modifier: self.Key.Modifier,
+	}
+	if self.Key.Code == "" {
+		r, size := utf8.DecodeRuneInString(self.Key.Rune)
+		if size == 0 || size != len(self.Key.Rune) {
+			return Key{}, fmt.Errorf("invalid key rune %q", self.Key.Rune)
+		}
+		key.Rune = r
+		return key, nil
+	}
+	code, ok := keyCodeByName[self.Key.Code]
+	if !ok {
+		return Key{}, fmt.Errorf("unknown key code %q", self.Key.Code)
+	}
+	key.Code = code
+	return key, nil
+}
+
+// ReplayBackend draws to another Backend but reads its events from a recording written by a
+// Recorder instead of from the terminal. Once the recording ends, events are read from the other
+// Backend again. Lines that can't be decoded are skipped, see Skipped. It is meant to reproduce
+// sessions, for instance together with HeadlessBackend:
+//
+//	f, _ := os.Open("session.jsonl")
+//	SetBackend(NewReplayBackend(NewHeadlessBackend(80, 24), f))
+type ReplayBackend struct {
+	Backend
+	// RealTime makes PollEvent wait between events as long as when they were recorded. Events are
+	// returned as fast as possible otherwise.
+	RealTime bool
+
+	sync.Mutex
+	scanner   *bufio.Scanner
+	line      int
+	last      time.Time // time of the last event returned
+	live      bool      // the recording ended
+	err       error
+	skipped   []error
+	interrupt chan struct{}
+}
+
+// maxRecordedEventSize is the size of the longest line ReplayBackend can read.
+const maxRecordedEventSize = 1 << 20
+
+func NewReplayBackend(b Backend, recording io.Reader) *ReplayBackend {
+	scanner := bufio.NewScanner(recording)
+	scanner.Buffer(nil, maxRecordedEventSize)
+	return &ReplayBackend{
+		Backend:   b,
+		scanner:   scanner,
+		interrupt: make(chan struct{}, 1),
+	}
+}
+
+// Err returns the error that ended the replay early, if any.
+func (self *ReplayBackend) Err() error {
+	self.Lock()
+	defer self.Unlock()
+	return self.err
+}
+
+// Skipped returns the errors of the lines of the recording that were skipped because they couldn't
+// be decoded, like events of unknown types.
+func (self *ReplayBackend) Skipped() []error {
+	self.Lock()
+	defer self.Unlock()
+	return append([]error{}, self.skipped...)
+}
+
+// Done reports whether the replay ended, at the end of the recording or on an error.
+func (self *ReplayBackend) Done() bool {
+	self.Lock()
+	defer self.Unlock()
+	return self.live
+}
+
+// PollEvent returns the next recorded event, then the events of the other Backend once the
+// recording ended.
+func (self *ReplayBackend) PollEvent() Event {
+	if self.Done() {
+		return self.Backend.PollEvent()
+	}
+	select {
+	case <-self.interrupt:
+		return Event{Type: InterruptEvent}
+	default:
+	}
+
+	e, at, err := self.read()
+	if err != nil {
+		self.Lock()
+		self.live = true
+		if err != io.EOF {
+			self.err = err
+		}
+		self.Unlock()
+		// an interrupt may have been sent before switching to the other Backend
+		select {
+		case <-self.interrupt:
+			return Event{Type: InterruptEvent}
+		default:
+		}
+		return self.Backend.PollEvent()
+	}
+
+	if self.RealTime && !self.last.IsZero() {
+		select {
+		case <-time.After(at.Sub(self.last)):
+		case <-self.interrupt:
+			return Event{Type: InterruptEvent}
+		}
+	}
+	self.last = at
+	return e
+}
+
+// read decodes the next recorded event and the time it was recorded at, skipping the lines that
+// can't be decoded.
+func (self *ReplayBackend) read() (Event, time.Time, error) {
+	for self.scanner.Scan() {
+		self.line++
+		if len(self.scanner.Bytes()) == 0 {
+			continue
+		}
+		var recorded recordedEvent
+		err := json.Unmarshal(self.scanner.Bytes(), &recorded)
+		if err == nil {
+			var e Event
+			if e, err = recorded.event(); err == nil {
+				return e, recorded.Time, nil
+			}
+		}
+		self.Lock()
+		self.skipped = append(self.skipped, fmt.Errorf("line %d: %v", self.line, err))
+		self.Unlock()
+	}
+	if err := self.scanner.Err(); err != nil {
+		return Event{}, time.Time{}, err
+	}
+	return Event{}, time.Time{}, io.EOF
+}
+
+// Interrupt makes the pending or next PollEvent return an InterruptEvent.
+func (self *ReplayBackend) Interrupt() {
+	self.Lock()
+	if self.live {
+		// the replay can't start again, the other Backend is interrupted instead
+		self.Unlock()
+		self.Backend.Interrupt()
+		return
+	}
+	select {
+	case self.interrupt <- struct{}{}:
+	default:
+	}
+	self.Unlock()
+}
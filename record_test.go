@@ -0,0 +1,81 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	events := []Event{
+		NewKeyboardEvent(Key{Rune: 'd', Modifier: KeyModCtrl}),
+		NewKeyboardEvent(Key{Code: KeyUp, Modifier: KeyModAlt}),
+		NewKeyboardEvent(Key{Rune: 'J', Modifier: KeyModShift}),
+		// a key whose ID alone doesn't say it is a Ctrl key
+		{Type: KeyboardEvent, ID: "<Tab>", Payload: Key{Code: KeyTab, Modifier: KeyModCtrl}},
+		{Type: MouseEvent, ID: "<MouseLeft>", Payload: Mouse{X: 3, Y: 4, Button: "<MouseLeft>", Clicks: 2}},
+		{Type: ResizeEvent, ID: "<Resize>", Payload: Resize{Width: 80, Height: 24}},
+		{Type: PasteEvent, ID: "<Paste>", Payload: "pasted\ntext"},
+		{Type: CustomEvent, ID: "tick", Payload: map[string]interface{}{"n": 1.0}},
+	}
+	var recording bytes.Buffer
+	recorder := NewRecorder(&recording)
+	for _, e := range events {
+		if err := recorder.Record(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	screen := NewHeadlessBackend(10, 10)
+	replay := NewReplayBackend(screen, &recording)
+	for _, want := range events {
+		if got := replay.PollEvent(); !reflect.DeepEqual(got, want) {
+			t.Errorf("replayed %#v, want %#v", got, want)
+		}
+	}
+
+	// the events of the other Backend follow the recording
+	live := NewKeyboardEvent(Key{Rune: 'q'})
+	screen.InjectEvent(live)
+	if e := replay.PollEvent(); e != live || !replay.Done() || replay.Err() != nil {
+		t.Errorf("after the recording got %v, done %v, err %v", e, replay.Done(), replay.Err())
+	}
+}
+
+func TestReplaySkipsBadLines(t *testing.T) {
+	recording := strings.Join([]string{
+		`{"time":"2019-07-15T10:00:00Z","type":"keyboard","id":"<C-d>"}`,
+		`{"time":"2019-07-15T10:00:01Z","type":"keyboard","id":""}`,
+		`not json`,
+		`{"time":"2019-07-15T10:00:02Z","type":"unknown","id":"x"}`,
+		`{"time":"2019-07-15T10:00:03Z","type":"keyboard","id":"<Up>","key":{"code":"Sideways"}}`,
+		``,
+		`{"time":"2019-07-15T10:00:04Z","type":"keyboard","id":"q","key":{"rune":"q"}}`,
+	}, "\n")
+	replay := NewReplayBackend(NewHeadlessBackend(10, 10), strings.NewReader(recording))
+
+	for _, want := range []Key{{Rune: 'd', Modifier: KeyModCtrl}, {Rune: 'q'}} {
+		if e := replay.PollEvent(); e.Payload != want {
+			t.Errorf("replayed %v, want %v", e.Payload, want)
+		}
+	}
+	replay.Interrupt()
+	replay.PollEvent()
+	skipped := replay.Skipped()
+	if len(skipped) != 4 {
+		t.Fatalf("skipped %v, want 4 lines", skipped)
+	}
+	for i, line := range []string{"line 2:", "line 3:", "line 4:", "line 5:"} {
+		if !strings.HasPrefix(skipped[i].Error(), line) {
+			t.Errorf("skipped[%d] = %v, want %s", i, skipped[i], line)
+		}
+	}
+	if replay.Err() != nil {
+		t.Errorf("Err() = %v", replay.Err())
+	}
+}
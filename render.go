@@ -7,8 +7,6 @@ package termui
 import (
 	"image"
 	"sync"
-
-	tb "github.com/nsf/termbox-go"
 )
 
 type Drawable interface {
@@ -25,48 +23,111 @@ type ConditionalDrawable interface {
 	Clean()
 }
 
-func Render(items ...Drawable) {
-	for _, item := range items {
-		buf := NewBuffer(item.GetRect())
-		item.Lock()
-		item.Draw(buf)
-		item.Unlock()
-		for point, cell := range buf.CellMap {
-			if point.In(buf.Rectangle) {
-				tb.SetCell(
-					point.X, point.Y,
-					cell.Rune,
-					tb.Attribute(cell.Style.Fg+1)|tb.Attribute(cell.Style.Modifier), tb.Attribute(cell.Style.Bg+1),
-				)
-			}
+// frame remembers every cell sent to the backend so that later renders only send the cells
+// that changed.
+type frame struct {
+	sync.Mutex
+	cells *Buffer // nil when the next render must send every cell
+}
+
+var lastFrame frame
+
+// cellUnknown marks cells of a frame that have not been sent to the backend.
+var cellUnknown = Cell{Rune: -1}
+
+func newFrameBuffer(r image.Rectangle) *Buffer {
+	buf := NewBuffer(r)
+	buf.Fill(cellUnknown, r)
+	return buf
+}
+
+// flush composes the buffers in order, so that later buffers overlap earlier ones, then sends
+// the cells that differ from the last frame to the backend.
+func (self *frame) flush(buffers []*Buffer) {
+	area := image.Rectangle{}
+	for _, buf := range buffers {
+		area = area.Union(buf.Rectangle)
+	}
+	next := newFrameBuffer(area)
+	for _, buf := range buffers {
+		next.SetBuffer(buf)
+	}
+
+	self.Lock()
+	defer self.Unlock()
+	forced := self.cells == nil
+	if forced {
+		self.cells = newFrameBuffer(area)
+	} else if !area.In(self.cells.Rectangle) {
+		grown := newFrameBuffer(self.cells.Union(area))
+		grown.SetBuffer(self.cells)
+		self.cells = grown
+	}
+	changed := false
+	next.Range(func(point image.Point, cell Cell) bool {
+		if cell == cellUnknown {
+			return true
 		}
+		i := self.cells.index(point)
+		if self.cells.Cells[i] != cell {
+			self.cells.Cells[i] = cell
+			backend.SetCell(point.X, point.Y, cell)
+			changed = true
+		}
+		return true
+	})
+	if changed || forced {
+		backend.Flush()
+	}
+}
+
+// invalidate discards the last frame so that the next render sends every cell.
+func (self *frame) invalidate() {
+	self.Lock()
+	self.cells = nil
+	self.Unlock()
+}
+
+// ForceRedraw makes the next call to Render or ConditionalRender send every cell to the backend
+// instead of only the cells that changed. Use it when the terminal contents were changed
+// outside of termui, e.g. after a resize. `Clear` does this automatically.
+func ForceRedraw() {
+	lastFrame.invalidate()
+}
+
+func drawItem(item Drawable) *Buffer {
+	buf := NewBuffer(item.GetRect())
+	item.Lock()
+	item.Draw(buf)
+	item.Unlock()
+	return buf
+}
+
+// Render draws the items in order and sends the cells that changed since the last render to
+// the backend.
+func Render(items ...Drawable) {
+	buffers := make([]*Buffer, len(items))
+	for i, item := range items {
+		buffers[i] = drawItem(item)
 	}
-	tb.Flush()
+	lastFrame.flush(buffers)
 }
 
+// ConditionalRender is like Render but only draws the items that are dirty, cleaning them.
 func ConditionalRender(items ...ConditionalDrawable) {
-	updateMade := false
+	buffers := []*Buffer{}
 	for _, item := range items {
 		if !item.IsDirty() {
 			continue
 		}
-		updateMade = true
 		buf := NewBuffer(item.GetRect())
 		item.Lock()
 		item.Draw(buf)
 		item.Clean()
 		item.Unlock()
-		for point, cell := range buf.CellMap {
-			if point.In(buf.Rectangle) {
-				tb.SetCell(
-					point.X, point.Y,
-					cell.Rune,
-					tb.Attribute(cell.Style.Fg+1)|tb.Attribute(cell.Style.Modifier), tb.Attribute(cell.Style.Bg+1),
-				)
-			}
-		}
+		buffers = append(buffers, buf)
 	}
-	if updateMade {
-		tb.Flush()
+	if len(buffers) > 0 {
+		lastFrame.flush(buffers)
 	}
 }
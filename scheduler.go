@@ -0,0 +1,215 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"context"
+	"image"
+	"sync"
+	"time"
+)
+
+// DefaultFrameRate is the number of frames per second a Scheduler draws at most.
+const DefaultFrameRate = 60
+
+// Scheduler coalesces redraw requests into frames, drawing at most FrameRate frames per second.
+// A frame draws the dirty ConditionalDrawables among the items and the children of their
+// Containers, and the Drawables that can't report changes; untouched widgets aren't drawn again.
+//
+// While it runs, Block.MarkDirty requests a frame, so widgets changed with their setters and update
+// methods, like Gauge.SetPercent or List.ScrollDown, are redrawn without calling Render. The
+// setters lock the widget, so they can be called from any goroutine, the other changes must be made
+// with the widget locked:
+//
+//	scheduler := NewScheduler(grid)
+//	go scheduler.Run(ctx)
+//	...
+//	gauge.SetPercent(50)
+//	list.Lock()
+//	list.ScrollDown()
+//	list.Unlock()
+type Scheduler struct {
+	sync.Mutex
+	FrameRate int
+
+	items     []Drawable
+	all       bool        // the next frame draws every item
+	last      time.Time   // time of the last frame
+	timer     *time.Timer // the pending frame, nil if none
+	requested chan struct{}
+}
+
+func NewScheduler(items ...Drawable) *Scheduler {
+	return &Scheduler{
+		FrameRate: DefaultFrameRate,
+		items:     items,
+		requested: make(chan struct{}, 1),
+	}
+}
+
+// SetItems replaces the items drawn by the frames and requests a frame drawing all of them.
+func (self *Scheduler) SetItems(items ...Drawable) {
+	self.Lock()
+	self.items = items
+	self.Unlock()
+	self.RequestAll()
+}
+
+// Request schedules a frame drawing the dirty items, as soon as the frame rate allows. Requests
+// made before the frame is drawn are coalesced into it. It can be called from any goroutine.
+func (self *Scheduler) Request() {
+	self.Lock()
+	defer self.Unlock()
+	if self.timer != nil {
+		return
+	}
+	delay := time.Duration(0)
+	if self.FrameRate > 0 {
+		delay = time.Until(self.last.Add(time.Second / time.Duration(self.FrameRate)))
+		if delay < 0 {
+			delay = 0
+		}
+	}
+	self.timer = time.NewTimer(delay)
+	select {
+	case self.requested <- struct{}{}:
+	default:
+	}
+}
+
+// RequestAll schedules a frame drawing every item, dirty or not, like after the terminal was
+// cleared.
+func (self *Scheduler) RequestAll() {
+	self.Lock()
+	self.all = true
+	self.Unlock()
+	self.Request()
+}
+
+// FrameTimer returns a channel receiving a value when the requested frame is due, to be drawn with
+// Frame, or nil if no frame was requested.
+func (self *Scheduler) FrameTimer() <-chan time.Time {
+	self.Lock()
+	defer self.Unlock()
+	if self.timer == nil {
+		return nil
+	}
+	return self.timer.C
+}
+
+// Frame draws the dirty items now, cleaning them, and sends the cells that changed to the backend.
+func (self *Scheduler) Frame() {
+	self.Lock()
+	if self.timer != nil {
+		self.timer.Stop()
+		self.timer = nil
+	}
+	items, all := self.items, self.all
+	self.all = false
+	self.last = time.Now()
+	self.Unlock()
+
+	buffers := []*Buffer{}
+	for _, item := range items {
+		buffers = appendDirtyBuffers(buffers, item, item.GetRect().Canon(), all)
+	}
+	if len(buffers) > 0 {
+		lastFrame.flush(buffers)
+	}
+}
+
+// appendDirtyBuffers draws the part of item inside clip if it is dirty, can't report changes or
+// all is set, and its dirty children otherwise. Containers are locked while their children are read,
+// since they can be added and removed from other goroutines.
+func appendDirtyBuffers(buffers []*Buffer, item Drawable, clip image.Rectangle, all bool) []*Buffer {
+	conditional, ok := item.(ConditionalDrawable)
+	item.Lock()
+	if all || !ok || conditional.IsDirty() {
+		buf := NewBuffer(item.GetRect().Canon().Intersect(clip))
+		item.Draw(buf)
+		if ok {
+			conditional.Clean()
+		}
+		cleanChildren(item)
+		item.Unlock()
+		return append(buffers, buf)
+	}
+	container, ok := item.(Container)
+	if !ok {
+		item.Unlock()
+		return buffers
+	}
+	inner := container.GetInnerRect().Intersect(clip)
+	children := container.Children()
+	item.Unlock()
+	for _, child := range children {
+		buffers = appendDirtyBuffers(buffers, child, inner, false)
+	}
+	return buffers
+}
+
+// cleanChildren cleans the children of a locked Container that was drawn with them.
+func cleanChildren(item Drawable) {
+	container, ok := item.(Container)
+	if !ok {
+		return
+	}
+	for _, child := range container.Children() {
+		child.Lock()
+		if conditional, ok := child.(ConditionalDrawable); ok {
+			conditional.Clean()
+		}
+		cleanChildren(child)
+		child.Unlock()
+	}
+}
+
+// Run draws the requested frames until the context is done. Frames are requested by Request and
+// by Block.MarkDirty.
+func (self *Scheduler) Run(ctx context.Context) {
+	schedulers.add(self)
+	defer schedulers.remove(self)
+	self.RequestAll()
+	for {
+		select {
+		case <-self.FrameTimer():
+			self.Frame()
+		case <-self.requested:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// schedulerSet holds the running Schedulers, which get the frame requests of Block.MarkDirty.
+type schedulerSet struct {
+	sync.Mutex
+	running map[*Scheduler]bool
+}
+
+var schedulers = schedulerSet{
+	running: make(map[*Scheduler]bool),
+}
+
+func (self *schedulerSet) add(s *Scheduler) {
+	self.Lock()
+	self.running[s] = true
+	self.Unlock()
+}
+
+func (self *schedulerSet) remove(s *Scheduler) {
+	self.Lock()
+	delete(self.running, s)
+	self.Unlock()
+}
+
+// requestFrames requests a frame from every running Scheduler.
+func requestFrames() {
+	schedulers.Lock()
+	defer schedulers.Unlock()
+	for s := range schedulers.running {
+		s.Request()
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"context"
+	"image"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// counter is a Block drawing its count, changed with a setter like the widgets'.
+type counter struct {
+	Block
+	count int
+	draws int
+}
+
+func newCounter() *counter {
+	c := &counter{Block: *NewBlock()}
+	c.Border = false
+	return c
+}
+
+func (self *counter) SetCount(count int) {
+	self.Lock()
+	self.count = count
+	self.Unlock()
+	self.MarkDirty()
+}
+
+func (self *counter) Draw(buf *Buffer) {
+	self.draws++
+	buf.SetString(strings.Repeat("#", self.count), StyleClear, self.Min)
+}
+
+func (self *counter) drawCount() int {
+	self.Lock()
+	defer self.Unlock()
+	return self.draws
+}
+
+// waitScreen waits until the row of the screen holds the text.
+func waitScreen(t *testing.T, screen *HeadlessBackend, y int, text string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got := screen.GetString(image.Rect(0, y, len(text), y+1))
+		if got == text {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("row %d is %q, want %q", y, got, text)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// runScheduler runs a Scheduler drawing items until the end of the test.
+func runScheduler(t *testing.T, items ...Drawable) {
+	scheduler := NewScheduler(items...)
+	scheduler.FrameRate = 1000
+	ctx, cancel := context.WithCancel(context.Background())
+	var running sync.WaitGroup
+	running.Add(1)
+	go func() {
+		defer running.Done()
+		scheduler.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		running.Wait()
+	})
+}
+
+func TestSchedulerSettersFromOtherGoroutines(t *testing.T) {
+	screen := initHeadless(t, 10, 2)
+	first, second := newCounter(), newCounter()
+	first.SetRect(0, 0, 10, 1)
+	second.SetRect(0, 1, 10, 2)
+	runScheduler(t, first, second)
+
+	var setters sync.WaitGroup
+	for _, c := range []*counter{first, second} {
+		setters.Add(1)
+		go func(c *counter) {
+			defer setters.Done()
+			for i := 0; i <= 10; i++ {
+				c.SetCount(i)
+				time.Sleep(100 * time.Microsecond)
+			}
+		}(c)
+	}
+	setters.Wait()
+	waitScreen(t, screen, 0, "##########")
+	waitScreen(t, screen, 1, "##########")
+}
+
+func TestSchedulerDrawsOnlyDirtyItems(t *testing.T) {
+	screen := initHeadless(t, 10, 2)
+	first, second := newCounter(), newCounter()
+	first.SetRect(0, 0, 10, 1)
+	second.SetRect(0, 1, 10, 2)
+	scheduler := NewScheduler(first, second)
+	scheduler.FrameRate = 0
+
+	scheduler.RequestAll()
+	<-scheduler.FrameTimer()
+	scheduler.Frame()
+	// the Scheduler isn't running, so the frame is requested by hand
+	first.SetCount(3)
+	scheduler.Request()
+	<-scheduler.FrameTimer()
+	scheduler.Frame()
+
+	waitScreen(t, screen, 0, "###")
+	if first.drawCount() != 2 || second.drawCount() != 1 {
+		t.Errorf("drew %d and %d times, want 2 and 1", first.drawCount(), second.drawCount())
+	}
+}
+
+func TestSchedulerPanelChangedFromOtherGoroutines(t *testing.T) {
+	screen := initHeadless(t, 10, 1)
+	panel := NewPanel()
+	// the border is off screen, the inner rectangle covers it
+	panel.SetRect(-1, -1, 11, 2)
+	runScheduler(t, panel)
+
+	counters := []*counter{}
+	for x := 0; x < 10; x++ {
+		c := newCounter()
+		c.SetCount(1)
+		panel.Add(c, x, 0, x+1, 1)
+		counters = append(counters, c)
+		time.Sleep(100 * time.Microsecond)
+	}
+	waitScreen(t, screen, 0, "##########")
+	for _, c := range counters[5:] {
+		panel.Remove(c)
+		time.Sleep(100 * time.Microsecond)
+	}
+	waitScreen(t, screen, 0, "#####     ")
+}
+
+func TestSchedulerFocusChangedFromOtherGoroutines(t *testing.T) {
+	screen := initHeadless(t, 6, 3)
+	first, second := NewBlock(), NewBlock()
+	first.SetRect(0, 0, 3, 3)
+	second.SetRect(3, 0, 6, 3)
+	focused := NewStyle(ColorYellow)
+	first.FocusedBorderStyle, second.FocusedBorderStyle = focused, focused
+	focus := NewFocusManager(first, second)
+	runScheduler(t, first, second)
+
+	for i := 0; i < 11; i++ {
+		focus.Next()
+		time.Sleep(100 * time.Microsecond)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for screen.GetStyle(0, 0) != first.BorderStyle || screen.GetStyle(3, 0) != focused {
+		if time.Now().After(deadline) {
+			t.Fatalf("border styles are %+v and %+v, want the second block focused",
+				screen.GetStyle(0, 0), screen.GetStyle(3, 0))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -1,6 +1,12 @@
 package termui
 
-// Color is an integer from -1 to 255
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Color is an integer from -1 to 255, or a 24-bit color created with NewRGBColor
 // -1 = ColorClear
 // 0-255 = Xterm colors
 type Color int
@@ -24,17 +30,84 @@ type Modifier uint
 
 const (
 	// ModifierClear clears any modifiers
-	ModifierClear     Modifier = 0
-	ModifierBold      Modifier = 1 << 9
-	ModifierUnderline Modifier = 1 << 10
-	ModifierReverse   Modifier = 1 << 11
+	ModifierClear         Modifier = 0
+	ModifierBold          Modifier = 1 << 9
+	ModifierUnderline     Modifier = 1 << 10
+	ModifierReverse       Modifier = 1 << 11
+	ModifierItalic        Modifier = 1 << 12
+	ModifierDim           Modifier = 1 << 13
+	ModifierStrikethrough Modifier = 1 << 14
+	ModifierBlink         Modifier = 1 << 15
+	// ModifierDoubleUnderline and ModifierCurlyUnderline fall back to a single underline on
+	// backends that don't support them.
+	ModifierDoubleUnderline Modifier = 1 << 16
+	ModifierCurlyUnderline  Modifier = 1 << 17
 )
 
+// String returns the color in the syntax used by ParseStyles: a name from StyleParserColorMap,
+// a hex color like #ff8800, or the palette number.
+func (self Color) String() string {
+	if self.IsRGB() {
+		r, g, b := self.RGB()
+		return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+	}
+	names := []string{}
+	for name, c := range StyleParserColorMap {
+		if c == self {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		return names[0]
+	}
+	return fmt.Sprint(int(self))
+}
+
+// String returns the modifiers in the syntax used by ParseStyles, like bold|italic.
+func (self Modifier) String() string {
+	if self == ModifierClear {
+		return "clear"
+	}
+	names := []string{}
+	for name, m := range modifierMap {
+		if self&m != 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, tokenModifierSeparator)
+}
+
 // Style represents the style of one terminal cell
 type Style struct {
 	Fg       Color
 	Bg       Color
 	Modifier Modifier
+	// underlineColor is the color of underlines plus one, so that the zero value, like ColorClear,
+	// uses the Fg color. See UnderlineColor and WithUnderlineColor.
+	underlineColor Color
+}
+
+// UnderlineColor returns the color of underlines, ColorClear if they use the Fg color.
+func (self Style) UnderlineColor() Color {
+	return self.underlineColor - 1
+}
+
+// WithUnderlineColor returns the style with underlines of the given color, ColorClear to use the Fg
+// color.
+func (self Style) WithUnderlineColor(c Color) Style {
+	self.underlineColor = c + 1
+	return self
+}
+
+// String returns the style in the syntax used by ParseStyles, like fg:red,bg:clear,mod:bold.
+func (self Style) String() string {
+	s := fmt.Sprintf("%s:%s,%s:%s,%s:%s", tokenFg, self.Fg, tokenBg, self.Bg, tokenModifier, self.Modifier)
+	if self.UnderlineColor() != ColorClear {
+		s += fmt.Sprintf(",%s:%s", tokenUnderlineColor, self.UnderlineColor())
+	}
+	return s
 }
 
 // StyleClear represents a default Style, with no colors or modifiers
@@ -44,22 +117,27 @@ var StyleClear = Style{
 	Modifier: ModifierClear,
 }
 
-// NewStyle takes 1 to 3 arguments
+// NewStyle takes 1 to 4 arguments
 // 1st argument = Fg
 // 2nd argument = optional Bg
 // 3rd argument = optional Modifier
+// 4th argument = optional UnderlineColor
 func NewStyle(fg Color, args ...interface{}) Style {
 	bg := ColorClear
 	modifier := ModifierClear
+	underlineColor := ColorClear
 	if len(args) >= 1 {
 		bg = args[0].(Color)
 	}
-	if len(args) == 2 {
+	if len(args) >= 2 {
 		modifier = args[1].(Modifier)
 	}
-	return Style{
-		fg,
-		bg,
-		modifier,
+	if len(args) == 3 {
+		underlineColor = args[2].(Color)
 	}
+	return Style{
+		Fg:       fg,
+		Bg:       bg,
+		Modifier: modifier,
+	}.WithUnderlineColor(underlineColor)
 }
@@ -5,16 +5,19 @@
 package termui
 
 import (
+	"strconv"
 	"strings"
 )
 
 const (
-	tokenFg       = "fg"
-	tokenBg       = "bg"
-	tokenModifier = "mod"
+	tokenFg             = "fg"
+	tokenBg             = "bg"
+	tokenModifier       = "mod"
+	tokenUnderlineColor = "ul"
 
-	tokenItemSeparator  = ","
-	tokenValueSeparator = ":"
+	tokenItemSeparator     = ","
+	tokenValueSeparator    = ":"
+	tokenModifierSeparator = "|"
 
 	tokenBeginStyledText = '['
 	tokenEndStyledText   = ']'
@@ -45,25 +48,55 @@ var StyleParserColorMap = map[string]Color{
 }
 
 var modifierMap = map[string]Modifier{
-	"bold":      ModifierBold,
-	"underline": ModifierUnderline,
-	"reverse":   ModifierReverse,
+	"bold":             ModifierBold,
+	"underline":        ModifierUnderline,
+	"reverse":          ModifierReverse,
+	"italic":           ModifierItalic,
+	"dim":              ModifierDim,
+	"strikethrough":    ModifierStrikethrough,
+	"blink":            ModifierBlink,
+	"double-underline": ModifierDoubleUnderline,
+	"curly-underline":  ModifierCurlyUnderline,
 }
 
-// readStyle translates an []rune like `fg:red,mod:bold,bg:white` to a style
-func readStyle(runes []rune, defaultStyle Style) Style {
+// parseColor translates a color name from StyleParserColorMap, a hex color like `#ff8800` or a
+// palette number like `208`
+func parseColor(s string) Color {
+	if len(s) == 7 && s[0] == '#' {
+		if rgb, err := strconv.ParseUint(s[1:], 16, 32); err == nil {
+			return NewRGBColor(uint8(rgb>>16), uint8(rgb>>8), uint8(rgb))
+		}
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= -1 && n < 256 {
+		return Color(n)
+	}
+	return StyleParserColorMap[s]
+}
+
+// readStyle translates a string like `fg:red,mod:bold|italic,bg:white` to a style
+// Modifiers can be combined with `|` or by repeating `mod`.
+func readStyle(items string, defaultStyle Style) Style {
 	style := defaultStyle
-	split := strings.Split(string(runes), tokenItemSeparator)
+	modifierSet := false
+	split := strings.Split(items, tokenItemSeparator)
 	for _, item := range split {
 		pair := strings.Split(item, tokenValueSeparator)
 		if len(pair) == 2 {
 			switch pair[0] {
 			case tokenFg:
-				style.Fg = StyleParserColorMap[pair[1]]
+				style.Fg = parseColor(pair[1])
 			case tokenBg:
-				style.Bg = StyleParserColorMap[pair[1]]
+				style.Bg = parseColor(pair[1])
+			case tokenUnderlineColor:
+				style = style.WithUnderlineColor(parseColor(pair[1]))
 			case tokenModifier:
-				style.Modifier = modifierMap[pair[1]]
+				if !modifierSet {
+					style.Modifier = ModifierClear
+					modifierSet = true
+				}
+				for _, name := range strings.Split(pair[1], tokenModifierSeparator) {
+					style.Modifier |= modifierMap[name]
+				}
 			}
 		}
 	}
@@ -72,81 +105,88 @@ func readStyle(runes []rune, defaultStyle Style) Style {
 
 // ParseStyles parses a string for embedded Styles and returns []Cell with the correct styling.
 // Uses defaultStyle for any text without an embedded style.
-// Syntax is of the form [text](fg:<color>,mod:<attribute>,bg:<color>).
+// Syntax is of the form [text](fg:<color>,mod:<attribute>,bg:<color>,ul:<color>).
+// Colors are names from StyleParserColorMap, hex colors like #ff8800 or palette numbers.
+// Attributes can be combined like mod:bold|italic, and ul sets the underline color.
 // Ordering does not matter. All fields are optional.
+// Each returned Cell holds one grapheme cluster.
 func ParseStyles(s string, defaultStyle Style) []Cell {
 	cells := []Cell{}
-	runes := []rune(s)
+	graphemes := SplitGraphemes(s)
 	state := parserStateDefault
-	styledText := []rune{}
-	styleItems := []rune{}
+	styledText := []string{}
+	styleItems := []string{}
 	squareCount := 0
 
 	reset := func() {
-		styledText = []rune{}
-		styleItems = []rune{}
+		styledText = []string{}
+		styleItems = []string{}
 		state = parserStateDefault
 		squareCount = 0
 	}
 
 	rollback := func() {
-		cells = append(cells, RunesToStyledCells(styledText, defaultStyle)...)
-		cells = append(cells, RunesToStyledCells(styleItems, defaultStyle)...)
+		cells = append(cells, GraphemesToStyledCells(styledText, defaultStyle)...)
+		cells = append(cells, GraphemesToStyledCells(styleItems, defaultStyle)...)
 		reset()
 	}
 
-	// chop first and last runes
-	chop := func(s []rune) []rune {
+	// chop first and last graphemes
+	chop := func(s []string) []string {
 		return s[1 : len(s)-1]
 	}
 
-	for i, _rune := range runes {
+	isToken := func(grapheme string, token rune) bool {
+		return grapheme == string(token)
+	}
+
+	for i, grapheme := range graphemes {
 		switch state {
 		case parserStateDefault:
-			if _rune == tokenBeginStyledText {
+			if isToken(grapheme, tokenBeginStyledText) {
 				state = parserStateStyledText
 				squareCount = 1
-				styledText = append(styledText, _rune)
+				styledText = append(styledText, grapheme)
 			} else {
-				cells = append(cells, Cell{_rune, defaultStyle})
+				cells = append(cells, NewGraphemeCell(grapheme, defaultStyle))
 			}
 		case parserStateStyledText:
 			switch {
 			case squareCount == 0:
-				switch _rune {
-				case tokenBeginStyle:
+				switch {
+				case isToken(grapheme, tokenBeginStyle):
 					state = parserStateStyleItems
-					styleItems = append(styleItems, _rune)
+					styleItems = append(styleItems, grapheme)
 				default:
 					rollback()
-					switch _rune {
-					case tokenBeginStyledText:
+					switch {
+					case isToken(grapheme, tokenBeginStyledText):
 						state = parserStateStyledText
 						squareCount = 1
-						styleItems = append(styleItems, _rune)
+						styleItems = append(styleItems, grapheme)
 					default:
-						cells = append(cells, Cell{_rune, defaultStyle})
+						cells = append(cells, NewGraphemeCell(grapheme, defaultStyle))
 					}
 				}
-			case len(runes) == i+1:
+			case len(graphemes) == i+1:
 				rollback()
-				styledText = append(styledText, _rune)
-			case _rune == tokenBeginStyledText:
+				styledText = append(styledText, grapheme)
+			case isToken(grapheme, tokenBeginStyledText):
 				squareCount++
-				styledText = append(styledText, _rune)
-			case _rune == tokenEndStyledText:
+				styledText = append(styledText, grapheme)
+			case isToken(grapheme, tokenEndStyledText):
 				squareCount--
-				styledText = append(styledText, _rune)
+				styledText = append(styledText, grapheme)
 			default:
-				styledText = append(styledText, _rune)
+				styledText = append(styledText, grapheme)
 			}
 		case parserStateStyleItems:
-			styleItems = append(styleItems, _rune)
-			if _rune == tokenEndStyle {
-				style := readStyle(chop(styleItems), defaultStyle)
-				cells = append(cells, RunesToStyledCells(chop(styledText), style)...)
+			styleItems = append(styleItems, grapheme)
+			if isToken(grapheme, tokenEndStyle) {
+				style := readStyle(strings.Join(chop(styleItems), ""), defaultStyle)
+				cells = append(cells, GraphemesToStyledCells(chop(styledText), style)...)
 				reset()
-			} else if len(runes) == i+1 {
+			} else if len(graphemes) == i+1 {
 				rollback()
 			}
 		}
@@ -0,0 +1,33 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "testing"
+
+func TestStyleUnderlineColorZeroValue(t *testing.T) {
+	if c := (Style{Fg: ColorRed}).UnderlineColor(); c != ColorClear {
+		t.Errorf("zero UnderlineColor = %v, want ColorClear", c)
+	}
+	if c := StyleClear.UnderlineColor(); c != ColorClear {
+		t.Errorf("StyleClear.UnderlineColor() = %v, want ColorClear", c)
+	}
+	if c := StyleClear.WithUnderlineColor(ColorBlack).UnderlineColor(); c != ColorBlack {
+		t.Errorf("UnderlineColor() = %v, want ColorBlack", c)
+	}
+	if s := NewStyle(ColorRed, ColorClear, ModifierUnderline); s != (Style{Fg: ColorRed, Bg: ColorClear, Modifier: ModifierUnderline}) {
+		t.Errorf("NewStyle without underline color = %v", s)
+	}
+}
+
+func TestParseStylesUnderlineColor(t *testing.T) {
+	style := StyleClear.WithUnderlineColor(ColorBlack)
+	cells := ParseStyles("[x](ul:black)", StyleClear)
+	if cells[0].Style != style {
+		t.Errorf("ParseStyles ul:black = %v, want %v", cells[0].Style, style)
+	}
+	if got := style.String(); got != "fg:clear,bg:clear,mod:clear,ul:black" {
+		t.Errorf("String() = %q", got)
+	}
+}
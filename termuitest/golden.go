@@ -0,0 +1,339 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+/*
+Package termuitest provides helpers for testing termui widgets.
+
+Golden files pin the rendered output of a widget. A test draws the widget and compares it with
+testdata/<name>.golden:
+
+	func TestParagraph(t *testing.T) {
+		p := widgets.NewParagraph()
+		p.Text = "Hello World!"
+		termuitest.AssertDrawable(t, "paragraph", p, 20, 3, termuitest.FormatStyled)
+	}
+
+Run the tests with `-termuitest.update` (or with an `-update` flag defined by the test package)
+to write the golden files instead of comparing against them.
+*/
+package termuitest
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ui "github.com/sparques/termui/v3"
+)
+
+var update = flag.Bool("termuitest.update", false, "update termuitest golden files")
+
+// Format selects what is serialized from a Buffer.
+type Format uint
+
+const (
+	// FormatText serializes only the text of the cells.
+	FormatText Format = iota
+	// FormatStyled serializes the text of the cells followed by a style layer.
+	FormatStyled
+)
+
+const (
+	stylesHeader = "-- styles --"
+	legendHeader = "-- legend --"
+)
+
+// styleKeys are the characters used to refer to styles in the style layer. Keys are one
+// character long, or as many as needed when there are more styles than characters.
+const styleKeys = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// maxReportedCells is the number of differing cells listed when an assertion fails.
+const maxReportedCells = 20
+
+// TestingT is the subset of testing.TB used by the assertions.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// Draw sets the rectangle of the Drawable to (0, 0, width, height) and draws it into a new
+// Buffer of that size.
+func Draw(d ui.Drawable, width, height int) *ui.Buffer {
+	d.SetRect(0, 0, width, height)
+	buf := ui.NewBuffer(image.Rect(0, 0, width, height))
+	d.Lock()
+	d.Draw(buf)
+	d.Unlock()
+	return buf
+}
+
+// Encode serializes a Buffer into a stable text form.
+// The text of each row is written on its own line. With FormatStyled, a style layer follows with
+// one key per cell referring to a legend of styles written in the syntax of ParseStyles. Keys are
+// single characters unless there are more than 62 styles.
+func Encode(buf *ui.Buffer, format Format) string {
+	return newSnapshot(buf, format).String()
+}
+
+// AssertDrawable draws the Drawable into a Buffer of the given size and compares it against the
+// golden file testdata/<name>.golden.
+func AssertDrawable(t TestingT, name string, d ui.Drawable, width, height int, format Format) {
+	t.Helper()
+	AssertBuffer(t, name, Draw(d, width, height), format)
+}
+
+// AssertBuffer compares a Buffer against the golden file testdata/<name>.golden.
+// When updating, the golden file is written instead.
+// Failures list the cells whose text or style changed and mark them on a map of the Buffer.
+func AssertBuffer(t TestingT, name string, buf *ui.Buffer, format Format) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	got := newSnapshot(buf, format)
+
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("creating golden file directory: %v", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(got.String()), 0644); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+		return
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file: %v (run with -termuitest.update to create it)", err)
+	}
+	want, err := parseSnapshot(string(data), got.width, format)
+	if err != nil {
+		t.Fatalf("parsing golden file %s: %v", path, err)
+	}
+	if report := diff(want, got); report != "" {
+		t.Errorf("%s does not match:\n%s\ngot:\n%s", path, report, got)
+	}
+}
+
+// updating reports whether golden files should be written, either with our own flag or with an
+// `-update` flag defined by the test package.
+func updating() bool {
+	if *update {
+		return true
+	}
+	if f := flag.Lookup("update"); f != nil {
+		return f.Value.String() == "true"
+	}
+	return false
+}
+
+// snapshot holds the serialized form of every cell, one column at a time.
+type snapshot struct {
+	width  int
+	height int
+	text   [][]string // text of each column, empty for continuation columns
+	styles [][]string // style of each column, nil for FormatText
+}
+
+func newSnapshot(buf *ui.Buffer, format Format) *snapshot {
+	self := &snapshot{
+		width:  buf.Dx(),
+		height: buf.Dy(),
+	}
+	for y := buf.Min.Y; y < buf.Max.Y; y++ {
+		textRow := make([]string, self.width)
+		styleRow := make([]string, self.width)
+		for x := buf.Min.X; x < buf.Max.X; x++ {
+			cell := buf.GetCell(image.Pt(x, y))
+			textRow[x-buf.Min.X] = cell.String()
+			styleRow[x-buf.Min.X] = cell.Style.String()
+		}
+		self.text = append(self.text, textRow)
+		if format == FormatStyled {
+			self.styles = append(self.styles, styleRow)
+		}
+	}
+	return self
+}
+
+// String encodes the snapshot. See Encode.
+func (self *snapshot) String() string {
+	var sb strings.Builder
+	for _, row := range self.text {
+		sb.WriteString(strings.Join(row, ""))
+		sb.WriteByte('\n')
+	}
+	if self.styles == nil {
+		return sb.String()
+	}
+
+	indexes := map[string]int{}
+	legend := []string{}
+	for _, row := range self.styles {
+		for _, style := range row {
+			if _, ok := indexes[style]; !ok {
+				indexes[style] = len(legend)
+				legend = append(legend, style)
+			}
+		}
+	}
+	size := 1
+	for n := len(styleKeys); n < len(legend); n *= len(styleKeys) {
+		size++
+	}
+
+	sb.WriteString(stylesHeader + "\n")
+	for _, row := range self.styles {
+		for _, style := range row {
+			sb.WriteString(styleKey(indexes[style], size))
+		}
+		sb.WriteByte('\n')
+	}
+	sb.WriteString(legendHeader + "\n")
+	for i, style := range legend {
+		fmt.Fprintf(&sb, "%s %s\n", styleKey(i, size), style)
+	}
+	return sb.String()
+}
+
+// styleKey returns the key of the i-th style of the legend, size characters long.
+func styleKey(i, size int) string {
+	key := make([]byte, size)
+	for j := size - 1; j >= 0; j-- {
+		key[j] = styleKeys[i%len(styleKeys)]
+		i /= len(styleKeys)
+	}
+	return string(key)
+}
+
+// parseSnapshot decodes a golden file. Rows are padded with spaces to the given width, so that
+// golden files whose trailing spaces were stripped by an editor still match.
+func parseSnapshot(data string, width int, format Format) (*snapshot, error) {
+	lines := strings.Split(strings.TrimSuffix(data, "\n"), "\n")
+	textLines := lines
+	stylesAt := indexOf(lines, stylesHeader)
+	if stylesAt >= 0 {
+		textLines = lines[:stylesAt]
+	}
+	self := &snapshot{
+		width:  width,
+		height: len(textLines),
+	}
+	for _, line := range textLines {
+		row := make([]string, 0, width)
+		for _, grapheme := range ui.SplitGraphemes(line) {
+			row = append(row, grapheme)
+			for i := 1; i < ui.StringWidth(grapheme); i++ {
+				row = append(row, "")
+			}
+		}
+		for len(row) < width {
+			row = append(row, " ")
+		}
+		self.text = append(self.text, row)
+	}
+	if format != FormatStyled {
+		return self, nil
+	}
+
+	if stylesAt < 0 {
+		return nil, fmt.Errorf("missing %q section", stylesHeader)
+	}
+	legendAt := indexOf(lines, legendHeader)
+	if legendAt < stylesAt {
+		return nil, fmt.Errorf("missing %q section", legendHeader)
+	}
+	// every key has the length of the first one
+	size := 1
+	if legendAt+1 < len(lines) {
+		size = strings.IndexByte(lines[legendAt+1], ' ')
+	}
+	legend := map[string]string{}
+	for _, line := range lines[legendAt+1:] {
+		if size < 1 || len(line) < size+1 || line[size] != ' ' {
+			return nil, fmt.Errorf("invalid legend line %q", line)
+		}
+		legend[line[:size]] = line[size+1:]
+	}
+	for _, line := range lines[stylesAt+1 : legendAt] {
+		if len(line)%size != 0 {
+			return nil, fmt.Errorf("style line %q is not made of %d character keys", line, size)
+		}
+		row := make([]string, 0, len(line)/size)
+		for i := 0; i < len(line); i += size {
+			style, ok := legend[line[i:i+size]]
+			if !ok {
+				return nil, fmt.Errorf("style %q is not in the legend", line[i:i+size])
+			}
+			row = append(row, style)
+		}
+		self.styles = append(self.styles, row)
+	}
+	return self, nil
+}
+
+func indexOf(lines []string, s string) int {
+	for i, line := range lines {
+		if line == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// diff describes the cells that differ between two snapshots, or returns "" if they match.
+func diff(want, got *snapshot) string {
+	if want.height != got.height {
+		return fmt.Sprintf("height is %d, want %d", got.height, want.height)
+	}
+
+	var sb strings.Builder
+	changed := 0
+	cellMap := make([][]byte, got.height)
+	for y := range got.text {
+		cellMap[y] = []byte(strings.Repeat(".", got.width))
+		for x := 0; x < got.width; x++ {
+			wantText := ""
+			if x < len(want.text[y]) {
+				wantText = want.text[y][x]
+			}
+			differences := []string{}
+			if got.text[y][x] != wantText {
+				differences = append(differences, fmt.Sprintf("text %q, want %q", got.text[y][x], wantText))
+			}
+			if got.styles != nil {
+				wantStyle := ""
+				if y < len(want.styles) && x < len(want.styles[y]) {
+					wantStyle = want.styles[y][x]
+				}
+				if got.styles[y][x] != wantStyle {
+					differences = append(differences, fmt.Sprintf("style %s, want %s", got.styles[y][x], wantStyle))
+				}
+			}
+			if len(differences) == 0 {
+				continue
+			}
+			cellMap[y][x] = 'X'
+			if changed < maxReportedCells {
+				fmt.Fprintf(&sb, "  (%d, %d): %s\n", x, y, strings.Join(differences, "; "))
+			}
+			changed++
+		}
+	}
+	if changed == 0 {
+		return ""
+	}
+	if changed > maxReportedCells {
+		fmt.Fprintf(&sb, "  ... and %d more\n", changed-maxReportedCells)
+	}
+	sb.WriteString("changed cells are marked with X:\n")
+	for _, row := range cellMap {
+		sb.Write(row)
+		sb.WriteByte('\n')
+	}
+	return fmt.Sprintf("%d cells differ:\n%s", changed, sb.String())
+}
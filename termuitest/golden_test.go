@@ -0,0 +1,147 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termuitest
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"testing"
+
+	ui "github.com/sparques/termui/v3"
+)
+
+// recorder is a TestingT remembering the failures instead of reporting them.
+type recorder struct {
+	errors []string
+	fatal  bool
+}
+
+func (self *recorder) Helper() {}
+
+func (self *recorder) Errorf(format string, args ...interface{}) {
+	self.errors = append(self.errors, fmt.Sprintf(format, args...))
+}
+
+func (self *recorder) Fatalf(format string, args ...interface{}) {
+	self.Errorf(format, args...)
+	self.fatal = true
+}
+
+func helloBuffer() *ui.Buffer {
+	buf := ui.NewBuffer(image.Rect(0, 0, 8, 2))
+	buf.SetString("hello", ui.NewStyle(ui.ColorRed), image.Pt(0, 0))
+	buf.SetString("世界", ui.NewStyle(ui.ColorBlue, ui.ColorClear, ui.ModifierBold), image.Pt(1, 1))
+	return buf
+}
+
+func TestEncode(t *testing.T) {
+	want := strings.Join([]string{
+		"hello   ",
+		" 世界   ",
+		stylesHeader,
+		"aaaaabbb",
+		"bccccbbb",
+		legendHeader,
+		"a fg:red,bg:clear,mod:clear",
+		"b fg:clear,bg:clear,mod:clear",
+		"c fg:blue,bg:clear,mod:bold",
+		"",
+	}, "\n")
+	if got := Encode(helloBuffer(), FormatStyled); got != want {
+		t.Errorf("Encode() =\n%s\nwant:\n%s", got, want)
+	}
+	if got := Encode(helloBuffer(), FormatText); got != "hello   \n 世界   \n" {
+		t.Errorf("Encode(FormatText) = %q", got)
+	}
+}
+
+func TestEncodeManyStyles(t *testing.T) {
+	buf := ui.NewBuffer(image.Rect(0, 0, 100, 2))
+	for x := 0; x < 100; x++ {
+		buf.SetCell(ui.NewCell('#', ui.NewStyle(ui.NewRGBColor(uint8(x), 0, 0))), image.Pt(x, 0))
+		buf.SetCell(ui.NewCell('#', ui.NewStyle(ui.ColorClear, ui.NewRGBColor(0, uint8(x), 0))), image.Pt(x, 1))
+	}
+	encoded := Encode(buf, FormatStyled)
+	lines := strings.Split(encoded, "\n")
+	if len(lines[3]) != 200 {
+		t.Fatalf("style line is %d characters long, want 2 per cell", len(lines[3]))
+	}
+	decoded, err := parseSnapshot(encoded, 100, FormatStyled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report := diff(decoded, newSnapshot(buf, FormatStyled)); report != "" {
+		t.Errorf("decoded snapshot differs:\n%s", report)
+	}
+	for x := 0; x < 100; x++ {
+		for y := 0; y < 2; y++ {
+			if want := buf.GetCell(image.Pt(x, y)).Style.String(); decoded.styles[y][x] != want {
+				t.Fatalf("style of (%d, %d) = %s, want %s", x, y, decoded.styles[y][x], want)
+			}
+		}
+	}
+}
+
+func TestParseSnapshotPadsRows(t *testing.T) {
+	s, err := parseSnapshot("ab\n", 4, FormatText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(s.text[0], ""); got != "ab  " {
+		t.Errorf("row = %q, want %q", got, "ab  ")
+	}
+}
+
+func TestParseSnapshotErrors(t *testing.T) {
+	tests := map[string]string{
+		"missing styles": "ab\n",
+		"missing legend": "ab\n-- styles --\naa\n",
+		"unknown key":    "ab\n-- styles --\nab\n-- legend --\na fg:red\n",
+		"bad legend":     "ab\n-- styles --\naa\n-- legend --\nafg:red\n",
+		"short keys":     "ab\n-- styles --\naaa\n-- legend --\naa fg:red\n",
+	}
+	for name, data := range tests {
+		if _, err := parseSnapshot(data, 2, FormatStyled); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
+
+func TestAssertBuffer(t *testing.T) {
+	r := &recorder{}
+	AssertBuffer(r, "hello", helloBuffer(), FormatStyled)
+	if len(r.errors) != 0 {
+		t.Fatalf("matching buffer failed: %v", r.errors)
+	}
+	if updating() {
+		t.Skip("checking the failures would update the golden file")
+	}
+
+	buf := helloBuffer()
+	buf.SetCell(ui.NewCell('J', ui.NewStyle(ui.ColorRed)), image.Pt(0, 0))
+	buf.SetCell(ui.NewCell('o', ui.NewStyle(ui.ColorGreen)), image.Pt(4, 0))
+	r = &recorder{}
+	AssertBuffer(r, "hello", buf, FormatStyled)
+	if len(r.errors) != 1 {
+		t.Fatalf("got %d failures, want 1", len(r.errors))
+	}
+	for _, want := range []string{"2 cells differ", `(0, 0): text "J", want "h"`, "(4, 0): style fg:green", "X...X..."} {
+		if !strings.Contains(r.errors[0], want) {
+			t.Errorf("failure doesn't contain %q:\n%s", want, r.errors[0])
+		}
+	}
+}
+
+func TestAssertBufferMissingFile(t *testing.T) {
+	if updating() {
+		t.Skip("updating writes missing golden files")
+	}
+	r := &recorder{}
+	AssertBuffer(r, "missing", helloBuffer(), FormatText)
+	if !r.fatal {
+		t.Error("missing golden file didn't fail")
+	}
+}
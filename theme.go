@@ -45,6 +45,9 @@ type RootTheme struct {
 type BlockTheme struct {
 	Title  Style
 	Border Style
+
+	FocusedTitle  Style
+	FocusedBorder Style
 }
 
 type BarChartTheme struct {
@@ -109,6 +112,9 @@ var Theme = RootTheme{
 	Block: BlockTheme{
 		Title:  NewStyle(ColorWhite),
 		Border: NewStyle(ColorWhite),
+
+		FocusedTitle:  NewStyle(ColorCyan, ColorClear, ModifierBold),
+		FocusedBorder: NewStyle(ColorCyan),
 	},
 
 	BarChart: BarChartTheme{
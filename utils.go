@@ -8,9 +8,13 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	rw "github.com/mattn/go-runewidth"
 	wordwrap "github.com/mitchellh/go-wordwrap"
+	"github.com/rivo/uniseg"
 )
 
 // InterfaceSlice takes an []interface{} represented as an interface{} and converts it
@@ -31,14 +35,45 @@ func InterfaceSlice(slice interface{}) []interface{} {
 }
 
 // TrimString trims a string to a max length and adds '…' to the end if it was trimmed.
+// The length is measured in columns, one grapheme cluster at a time.
 func TrimString(s string, w int) string {
 	if w <= 0 {
 		return ""
 	}
-	if rw.StringWidth(s) > w {
-		return rw.Truncate(s, w, string(ELLIPSES))
+	if StringWidth(s) <= w {
+		return s
 	}
-	return s
+	var sb strings.Builder
+	width := rw.RuneWidth(ELLIPSES)
+	for _, grapheme := range SplitGraphemes(s) {
+		width += graphemeWidth(grapheme)
+		if width > w {
+			break
+		}
+		sb.WriteString(grapheme)
+	}
+	sb.WriteRune(ELLIPSES)
+	return sb.String()
+}
+
+// StringWidth returns the number of columns a string occupies, measured one grapheme cluster
+// at a time.
+func StringWidth(s string) int {
+	width := 0
+	for _, grapheme := range SplitGraphemes(s) {
+		width += graphemeWidth(grapheme)
+	}
+	return width
+}
+
+// graphemeWidth returns the width of a single grapheme cluster. Single runes use runewidth so
+// that its East Asian width settings apply.
+func graphemeWidth(grapheme string) int {
+	if utf8.RuneCountInString(grapheme) == 1 {
+		r, _ := utf8.DecodeRuneInString(grapheme)
+		return rw.RuneWidth(r)
+	}
+	return uniseg.StringWidth(grapheme)
 }
 
 func SelectColor(colors []Color, index int) Color {
@@ -183,44 +218,115 @@ func MinInt(x, y int) int {
 
 // WrapCells takes []Cell and inserts Cells containing '\n' wherever a linebreak should go.
 func WrapCells(cells []Cell, width uint) []Cell {
-	str := CellsToString(cells)
-	wrapped := wordwrap.WrapString(str, width)
+	wrapped := []rune(wordwrap.WrapString(CellsToString(cells), width))
 	wrappedCells := []Cell{}
-	i := 0
-	for _, _rune := range wrapped {
-		if _rune == '\n' {
-			wrappedCells = append(wrappedCells, Cell{_rune, StyleClear})
-		} else {
-			wrappedCells = append(wrappedCells, Cell{_rune, cells[i].Style})
+	i := 0 // position in wrapped of the next cell
+
+	// skip moves past the runes of a cell that wordwrap kept, leaving out the ones it dropped
+	skip := func(runes []rune) {
+		for _, r := range runes {
+			if i < len(wrapped) && wrapped[i] == r {
+				i++
+			}
+		}
+	}
+
+	for _, cell := range cells {
+		runes := []rune(cell.String())
+		if len(runes) == 0 {
+			continue
+		}
+		if i < len(wrapped) && wrapped[i] == '\n' && runes[0] != '\n' {
+			// a linebreak inserted by wordwrap, which replaces the whitespace cell it breaks at
+			wrappedCells = append(wrappedCells, NewCell('\n', StyleClear))
+			i++
+			if unicode.IsSpace(runes[0]) && runes[0] != nbsp {
+				skip(runes[1:])
+				continue
+			}
+		}
+		switch {
+		case !hasRunePrefix(wrapped[i:], runes):
+			// whitespace dropped by wordwrap
+			skip(runes)
+		case runes[0] == '\n':
+			wrappedCells = append(wrappedCells, NewCell('\n', StyleClear))
+			i++
+		default:
+			wrappedCells = append(wrappedCells, cell)
+			i += len(runes)
 		}
-		i++
 	}
 	return wrappedCells
 }
 
+// nbsp is the no-break space, which wordwrap doesn't break at.
+const nbsp = '\u00a0'
+
+func hasRunePrefix(runes, prefix []rune) bool {
+	if len(runes) < len(prefix) {
+		return false
+	}
+	for i, r := range prefix {
+		if runes[i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// SplitGraphemes splits a string into its grapheme clusters.
+func SplitGraphemes(s string) []string {
+	graphemes := []string{}
+	state := -1
+	for len(s) > 0 {
+		var cluster string
+		cluster, s, _, state = uniseg.FirstGraphemeClusterInString(s, state)
+		graphemes = append(graphemes, cluster)
+	}
+	return graphemes
+}
+
 func RunesToStyledCells(runes []rune, style Style) []Cell {
 	cells := []Cell{}
 	for _, _rune := range runes {
-		cells = append(cells, Cell{_rune, style})
+		cells = append(cells, NewCell(_rune, style))
+	}
+	return cells
+}
+
+// GraphemesToStyledCells returns one Cell per grapheme cluster.
+func GraphemesToStyledCells(graphemes []string, style Style) []Cell {
+	cells := []Cell{}
+	for _, grapheme := range graphemes {
+		cells = append(cells, NewGraphemeCell(grapheme, style))
 	}
 	return cells
 }
 
 func CellsToString(cells []Cell) string {
-	runes := make([]rune, len(cells))
-	for i, cell := range cells {
-		runes[i] = cell.Rune
+	var sb strings.Builder
+	for _, cell := range cells {
+		sb.WriteString(cell.String())
+	}
+	return sb.String()
+}
+
+// CellsWidth returns the number of columns the cells occupy.
+func CellsWidth(cells []Cell) int {
+	width := 0
+	for _, cell := range cells {
+		width += cell.Width()
 	}
-	return string(runes)
+	return width
 }
 
 func TrimCells(cells []Cell, w int) []Cell {
 	s := CellsToString(cells)
 	s = TrimString(s, w)
-	runes := []rune(s)
 	newCells := []Cell{}
-	for i, r := range runes {
-		newCells = append(newCells, Cell{r, cells[i].Style})
+	for i, grapheme := range SplitGraphemes(s) {
+		newCells = append(newCells, NewGraphemeCell(grapheme, cells[i].Style))
 	}
 	return newCells
 }
@@ -229,7 +335,7 @@ func SplitCells(cells []Cell, r rune) [][]Cell {
 	splitCells := [][]Cell{}
 	temp := []Cell{}
 	for _, cell := range cells {
-		if cell.Rune == r {
+		if cell.Rune == r && cell.Grapheme == "" {
 			splitCells = append(splitCells, temp)
 			temp = []Cell{}
 		} else {
@@ -252,7 +358,7 @@ func BuildCellWithXArray(cells []Cell) []CellWithX {
 	index := 0
 	for i, cell := range cells {
 		cellWithXArray[i] = CellWithX{X: index, Cell: cell}
-		index += rw.RuneWidth(cell.Rune)
+		index += cell.Width()
 	}
 	return cellWithXArray
 }
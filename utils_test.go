@@ -0,0 +1,45 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "testing"
+
+func TestWrapCells(t *testing.T) {
+	tests := []struct {
+		text  string
+		width uint
+		want  string
+	}{
+		{"aaaa bbbb cccc", 4, "aaaa\nbbbb\ncccc"},
+		{"aaaa   bbbb", 6, "aaaa\nbbbb"},
+		{"aaaa\nbb cc", 10, "aaaa\nbb cc"},
+		{"trailing   ", 8, "trailing"},
+		{"世界 世界", 4, "世界\n世界"},
+		// the space carrying the accent is replaced by the linebreak
+		{"aaaa \u0301bbbb cccc", 4, "aaaa\nbbbb\ncccc"},
+		{"aa \u0301bb", 10, "aa \u0301bb"},
+		{"a b c", 3, "a b\nc"},
+	}
+	for _, test := range tests {
+		cells := ParseStyles(test.text, StyleClear)
+		if got := CellsToString(WrapCells(cells, test.width)); got != test.want {
+			t.Errorf("WrapCells(%q, %d) = %q, want %q", test.text, test.width, got, test.want)
+		}
+	}
+}
+
+func TestWrapCellsKeepsStyles(t *testing.T) {
+	red := NewStyle(ColorRed)
+	cells := ParseStyles("ab [cd](fg:red) ef", StyleClear)
+	wrapped := WrapCells(cells, 5)
+	if got := CellsToString(wrapped); got != "ab cd\nef" {
+		t.Fatalf("wrapped = %q", got)
+	}
+	for i, want := range []Style{StyleClear, StyleClear, StyleClear, red, red, StyleClear, StyleClear, StyleClear} {
+		if wrapped[i].Style != want {
+			t.Errorf("cell %d %q: style = %+v, want %+v", i, wrapped[i].String(), wrapped[i].Style, want)
+		}
+	}
+}
@@ -8,8 +8,6 @@ import (
 	"fmt"
 	"image"
 
-	rw "github.com/mattn/go-runewidth"
-
 	. "github.com/sparques/termui/v3"
 )
 
@@ -38,6 +36,15 @@ func NewBarChart() *BarChart {
 	}
 }
 
+// SetData sets Data and Labels and marks the chart dirty.
+func (self *BarChart) SetData(data []float64, labels ...string) {
+	self.Lock()
+	self.Data = data
+	self.Labels = labels
+	self.Unlock()
+	self.MarkDirty()
+}
+
 func (self *BarChart) Draw(buf *Buffer) {
 	self.Block.Draw(buf)
 
@@ -62,7 +69,7 @@ func (self *BarChart) Draw(buf *Buffer) {
 		if i < len(self.Labels) {
 			labelXCoordinate := barXCoordinate +
 				int((float64(self.BarWidth) / 2)) -
-				int((float64(rw.StringWidth(self.Labels[i])) / 2))
+				int((float64(StringWidth(self.Labels[i])) / 2))
 			buf.SetString(
 				self.Labels[i],
 				SelectStyle(self.LabelStyles, i),
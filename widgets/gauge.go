@@ -29,6 +29,22 @@ func NewGauge() *Gauge {
 	}
 }
 
+// SetPercent sets Percent and marks the gauge dirty.
+func (self *Gauge) SetPercent(percent float64) {
+	self.Lock()
+	self.Percent = percent
+	self.Unlock()
+	self.MarkDirty()
+}
+
+// SetLabel sets Label and marks the gauge dirty.
+func (self *Gauge) SetLabel(label string) {
+	self.Lock()
+	self.Label = label
+	self.Unlock()
+	self.MarkDirty()
+}
+
 func (self *Gauge) Draw(buf *Buffer) {
 	self.Block.Draw(buf)
 
@@ -58,15 +74,16 @@ func (self *Gauge) Draw(buf *Buffer) {
 	}
 
 	// plot label
-	labelXCoordinate := self.Inner.Min.X + (self.Inner.Dx() / 2) - int(float64(len(label))/2)
+	labelXCoordinate := self.Inner.Min.X + (self.Inner.Dx() / 2) - int(float64(StringWidth(label))/2)
 	labelYCoordinate := self.Inner.Min.Y + ((self.Inner.Dy() - 1) / 2)
 	if labelYCoordinate < self.Inner.Max.Y {
-		for i, char := range label {
-			if labelXCoordinate+i+1 <= self.Inner.Min.X+barWidth {
-				buf.SetCell(NewCell(char, self.LabelOnBarStyle), image.Pt(labelXCoordinate+i, labelYCoordinate))
-				continue
+		for _, grapheme := range SplitGraphemes(label) {
+			if labelXCoordinate+1 <= self.Inner.Min.X+barWidth {
+				buf.SetCell(NewGraphemeCell(grapheme, self.LabelOnBarStyle), image.Pt(labelXCoordinate, labelYCoordinate))
+			} else {
+				buf.SetCell(NewGraphemeCell(grapheme, self.LabelStyle), image.Pt(labelXCoordinate, labelYCoordinate))
 			}
-			buf.SetCell(NewCell(char, self.LabelStyle), image.Pt(labelXCoordinate+i, labelYCoordinate))
+			labelXCoordinate += StringWidth(grapheme)
 		}
 	}
 }
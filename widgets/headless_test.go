@@ -0,0 +1,105 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package widgets_test
+
+import (
+	"image"
+	"testing"
+
+	ui "github.com/sparques/termui/v3"
+	"github.com/sparques/termui/v3/widgets"
+)
+
+// initScreen renders to a HeadlessBackend of the given size for the rest of the test.
+func initScreen(t *testing.T, width, height int) *ui.HeadlessBackend {
+	t.Helper()
+	previous := ui.GetBackend()
+	screen := ui.NewHeadlessBackend(width, height)
+	ui.SetBackend(screen)
+	if err := ui.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		ui.Close()
+		ui.SetBackend(previous)
+	})
+	return screen
+}
+
+func expectScreen(t *testing.T, screen *ui.HeadlessBackend, rect image.Rectangle, want string) {
+	t.Helper()
+	if got := screen.GetString(rect); got != want {
+		t.Errorf("screen:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestParagraphRender(t *testing.T) {
+	screen := initScreen(t, 12, 3)
+	p := widgets.NewParagraph()
+	p.Text = "a [red](fg:red) word"
+	p.SetRect(0, 0, 12, 3)
+	ui.Render(p)
+
+	expectScreen(t, screen, image.Rect(1, 1, 11, 2), "a red word")
+	if style := screen.GetStyle(3, 1); style.Fg != ui.ColorRed {
+		t.Errorf("style = %+v, want a red foreground", style)
+	}
+	if style := screen.GetStyle(1, 1); style != p.TextStyle {
+		t.Errorf("style = %+v, want %+v", style, p.TextStyle)
+	}
+
+	p.SetText("changed")
+	ui.ConditionalRender(p)
+	expectScreen(t, screen, image.Rect(1, 1, 11, 2), "changed   ")
+}
+
+func TestListRenderScrolls(t *testing.T) {
+	screen := initScreen(t, 8, 4)
+	l := widgets.NewList()
+	l.Rows = []string{"one", "two", "three", "four"}
+	l.SelectedRowStyle = ui.NewStyle(ui.ColorYellow)
+	l.SetRect(0, 0, 8, 4)
+	ui.Render(l)
+	expectScreen(t, screen, image.Rect(1, 1, 7, 3), "one   \ntwo  ▼")
+
+	for i := 0; i < 3; i++ {
+		l.ScrollDown()
+	}
+	if !l.IsDirty() {
+		t.Fatal("list isn't dirty after scrolling")
+	}
+	ui.ConditionalRender(l)
+	expectScreen(t, screen, image.Rect(1, 1, 7, 3), "three▲\nfour  ")
+	if style := screen.GetStyle(1, 2); style.Fg != ui.ColorYellow {
+		t.Errorf("selected row style = %+v, want a yellow foreground", style)
+	}
+	if l.IsDirty() {
+		t.Error("list is still dirty after ConditionalRender")
+	}
+}
+
+func TestGaugeRenderUpdates(t *testing.T) {
+	screen := initScreen(t, 12, 3)
+	g := widgets.NewGauge()
+	g.BarColor = ui.ColorBlue
+	g.Label = "half"
+	g.SetRect(0, 0, 12, 3)
+	g.SetPercent(0.5)
+	ui.Render(g)
+
+	expectScreen(t, screen, image.Rect(1, 1, 11, 2), "   half   ")
+	if bg := screen.GetStyle(1, 1).Bg; bg != ui.ColorBlue {
+		t.Errorf("bar background = %v, want blue", bg)
+	}
+	if bg := screen.GetStyle(8, 1).Bg; bg == ui.ColorBlue {
+		t.Error("the bar covers more than half of the gauge")
+	}
+
+	g.SetPercent(1)
+	ui.ConditionalRender(g)
+	if bg := screen.GetStyle(10, 1).Bg; bg != ui.ColorBlue {
+		t.Errorf("full bar background = %v, want blue", bg)
+	}
+}
@@ -27,6 +27,14 @@ func NewImage(img image.Image) *Image {
 	}
 }
 
+// SetImage sets Image and marks the widget dirty.
+func (self *Image) SetImage(img image.Image) {
+	self.Lock()
+	self.Image = img
+	self.Unlock()
+	self.MarkDirty()
+}
+
 func (self *Image) Draw(buf *Buffer) {
 	self.Block.Draw(buf)
 
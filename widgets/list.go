@@ -7,8 +7,6 @@ package widgets
 import (
 	"image"
 
-	rw "github.com/mattn/go-runewidth"
-
 	. "github.com/sparques/termui/v3"
 )
 
@@ -30,6 +28,14 @@ func NewList() *List {
 	}
 }
 
+// SetRows sets Rows and marks the list dirty.
+func (self *List) SetRows(rows []string) {
+	self.Lock()
+	self.Rows = rows
+	self.Unlock()
+	self.MarkDirty()
+}
+
 func (self *List) Draw(buf *Buffer) {
 	self.Block.Draw(buf)
 
@@ -60,8 +66,10 @@ func (self *List) Draw(buf *Buffer) {
 					buf.SetCell(NewCell(ELLIPSES, style), point.Add(image.Pt(-1, 0)))
 					break
 				} else {
-					buf.SetCell(NewCell(cells[j].Rune, style), point)
-					point = point.Add(image.Pt(rw.RuneWidth(cells[j].Rune), 0))
+					cell := cells[j]
+					cell.Style = style
+					buf.SetCell(cell, point)
+					point = point.Add(image.Pt(cell.Width(), 0))
 				}
 			}
 		}
@@ -96,6 +104,7 @@ func (self *List) ScrollAmount(amount int) {
 	} else {
 		self.SelectedRow += amount
 	}
+	self.MarkDirty()
 }
 
 func (self *List) ScrollUp() {
@@ -110,6 +119,7 @@ func (self *List) ScrollPageUp() {
 	// If an item is selected below top row, then go to the top row.
 	if self.SelectedRow > self.topRow {
 		self.SelectedRow = self.topRow
+		self.MarkDirty()
 	} else {
 		self.ScrollAmount(-self.Inner.Dy())
 	}
@@ -129,8 +139,10 @@ func (self *List) ScrollHalfPageDown() {
 
 func (self *List) ScrollTop() {
 	self.SelectedRow = 0
+	self.MarkDirty()
 }
 
 func (self *List) ScrollBottom() {
 	self.SelectedRow = len(self.Rows) - 1
+	self.MarkDirty()
 }
@@ -25,6 +25,14 @@ func NewParagraph() *Paragraph {
 	}
 }
 
+// SetText sets Text and marks the paragraph dirty.
+func (self *Paragraph) SetText(text string) {
+	self.Lock()
+	self.Text = text
+	self.Unlock()
+	self.MarkDirty()
+}
+
 func (self *Paragraph) Draw(buf *Buffer) {
 	self.Block.Draw(buf)
 
@@ -34,6 +34,14 @@ func NewPieChart() *PieChart {
 	}
 }
 
+// SetData sets Data and marks the chart dirty.
+func (self *PieChart) SetData(data []float64) {
+	self.Lock()
+	self.Data = data
+	self.Unlock()
+	self.MarkDirty()
+}
+
 func (self *PieChart) Draw(buf *Buffer) {
 	self.Block.Draw(buf)
 
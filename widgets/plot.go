@@ -86,6 +86,26 @@ func NewPlot() *Plot {
 	}
 }
 
+// SetData sets Data and marks the plot dirty.
+func (self *Plot) SetData(data [][]float64) {
+	self.Lock()
+	self.Data = data
+	self.Unlock()
+	self.MarkDirty()
+}
+
+// AppendData appends values to the line at index i, adding the missing lines, and marks the plot
+// dirty.
+func (self *Plot) AppendData(i int, values ...float64) {
+	self.Lock()
+	for len(self.Data) <= i {
+		self.Data = append(self.Data, []float64{})
+	}
+	self.Data[i] = append(self.Data[i], values...)
+	self.Unlock()
+	self.MarkDirty()
+}
+
 func (self *Plot) renderBraille(buf *Buffer, drawArea image.Rectangle, maxVal, minVal float64) {
 	canvas := NewCanvas()
 	canvas.Rectangle = drawArea
@@ -42,6 +42,22 @@ func NewSparklineGroup(sls ...*Sparkline) *SparklineGroup {
 	}
 }
 
+// SetData sets the Data of the sparkline at index i and marks the group dirty.
+func (self *SparklineGroup) SetData(i int, data []float64) {
+	self.Lock()
+	self.Sparklines[i].Data = data
+	self.Unlock()
+	self.MarkDirty()
+}
+
+// AppendData appends values to the Data of the sparkline at index i and marks the group dirty.
+func (self *SparklineGroup) AppendData(i int, values ...float64) {
+	self.Lock()
+	self.Sparklines[i].Data = append(self.Sparklines[i].Data, values...)
+	self.Unlock()
+	self.MarkDirty()
+}
+
 func (self *SparklineGroup) Draw(buf *Buffer) {
 	self.Block.Draw(buf)
 
@@ -8,8 +8,6 @@ import (
 	"fmt"
 	"image"
 
-	rw "github.com/mattn/go-runewidth"
-
 	. "github.com/sparques/termui/v3"
 )
 
@@ -38,6 +36,15 @@ func NewStackedBarChart() *StackedBarChart {
 	}
 }
 
+// SetData sets Data and Labels and marks the chart dirty.
+func (self *StackedBarChart) SetData(data [][]float64, labels ...string) {
+	self.Lock()
+	self.Data = data
+	self.Labels = labels
+	self.Unlock()
+	self.MarkDirty()
+}
+
 func (self *StackedBarChart) Draw(buf *Buffer) {
 	self.Block.Draw(buf)
 
@@ -81,7 +88,7 @@ func (self *StackedBarChart) Draw(buf *Buffer) {
 		// draw label
 		if i < len(self.Labels) {
 			labelXCoordinate := barXCoordinate + MaxInt(
-				int((float64(self.BarWidth)/2))-int((float64(rw.StringWidth(self.Labels[i]))/2)),
+				int((float64(self.BarWidth)/2))-int((float64(StringWidth(self.Labels[i]))/2)),
 				0,
 			)
 			buf.SetString(
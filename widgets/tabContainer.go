@@ -33,6 +33,14 @@ func NewTabContainer(tabs ...Drawable) *TabContainer {
 	}
 }
 
+// SetActiveTab sets ActiveTabIndex and marks the container dirty.
+func (self *TabContainer) SetActiveTab(i int) {
+	self.Lock()
+	self.ActiveTabIndex = i
+	self.Unlock()
+	self.MarkDirty()
+}
+
 func (self *TabContainer) ActiveTab() Drawable {
 	return self.Tabs[self.ActiveTabIndex]
 }
@@ -43,6 +51,7 @@ func (self *TabContainer) FocusLeft() {
 	} else {
 		self.ActiveTabIndex = len(self.Tabs) - 1
 	}
+	self.MarkDirty()
 }
 
 func (self *TabContainer) FocusRight() {
@@ -51,6 +60,7 @@ func (self *TabContainer) FocusRight() {
 	} else {
 		self.ActiveTabIndex = 0
 	}
+	self.MarkDirty()
 }
 
 func (self *TabContainer) Draw(buf *Buffer) {
@@ -73,5 +83,13 @@ func (self *TabContainer) Draw(buf *Buffer) {
 	// do this last so we can show the updated title
 	self.Block.Draw(buf)
 	self.Tabs[self.ActiveTabIndex].SetRect(self.Inner.Min.X, self.Inner.Min.Y, self.Inner.Max.X, self.Inner.Max.Y)
-	self.Tabs[self.ActiveTabIndex].Draw(buf)
+	DrawChild(buf, self, self.Tabs[self.ActiveTabIndex])
+}
+
+// Children returns the active tab, the only one visible.
+func (self *TabContainer) Children() []Drawable {
+	if self.ActiveTabIndex < 0 || self.ActiveTabIndex >= len(self.Tabs) {
+		return nil
+	}
+	return []Drawable{self.ActiveTab()}
 }
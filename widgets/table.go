@@ -44,6 +44,14 @@ func NewTable() *Table {
 	}
 }
 
+// SetRows sets Rows and marks the table dirty.
+func (self *Table) SetRows(rows [][]string) {
+	self.Lock()
+	self.Rows = rows
+	self.Unlock()
+	self.MarkDirty()
+}
+
 func (self *Table) Draw(buf *Buffer) {
 	self.Block.Draw(buf)
 
@@ -83,6 +91,7 @@ func (self *Table) Draw(buf *Buffer) {
 		// draw row cells
 		for j := 0; j < len(row); j++ {
 			col := ParseStyles(row[j], rowStyle)
+			colWidth := CellsWidth(col)
 			colAlign := self.TextAlignment
 			if j < len(self.ColumnAlignment) {
 				colAlign = self.ColumnAlignment[j]
@@ -91,26 +100,25 @@ func (self *Table) Draw(buf *Buffer) {
 			}
 			// draw row cell
 			switch {
-			case len(col) > columnWidths[j] || colAlign == AlignLeft:
+			case colWidth > columnWidths[j] || colAlign == AlignLeft:
 				for _, cx := range BuildCellWithXArray(col) {
 					k, cell := cx.X, cx.Cell
 					if k == columnWidths[j] || colXCoordinate+k == self.Inner.Max.X {
-						cell.Rune = ELLIPSES
-						buf.SetCell(cell, image.Pt(colXCoordinate+k-1, yCoordinate))
+						buf.SetCell(NewCell(ELLIPSES, cell.Style), image.Pt(colXCoordinate+k-1, yCoordinate))
 						break
 					} else {
 						buf.SetCell(cell, image.Pt(colXCoordinate+k, yCoordinate))
 					}
 				}
 			case colAlign == AlignCenter:
-				xCoordinateOffset := (columnWidths[j] - len(col)) / 2
+				xCoordinateOffset := (columnWidths[j] - colWidth) / 2
 				stringXCoordinate := xCoordinateOffset + colXCoordinate
 				for _, cx := range BuildCellWithXArray(col) {
 					k, cell := cx.X, cx.Cell
 					buf.SetCell(cell, image.Pt(stringXCoordinate+k, yCoordinate))
 				}
 			case colAlign == AlignRight:
-				stringXCoordinate := MinInt(colXCoordinate+columnWidths[j], self.Inner.Max.X) - len(col)
+				stringXCoordinate := MinInt(colXCoordinate+columnWidths[j], self.Inner.Max.X) - colWidth
 				for _, cx := range BuildCellWithXArray(col) {
 					k, cell := cx.X, cx.Cell
 					buf.SetCell(cell, image.Pt(stringXCoordinate+k, yCoordinate))
@@ -30,16 +30,26 @@ func NewTabPane(names ...string) *TabPane {
 	}
 }
 
+// SetActiveTab sets ActiveTabIndex and marks the pane dirty.
+func (self *TabPane) SetActiveTab(i int) {
+	self.Lock()
+	self.ActiveTabIndex = i
+	self.Unlock()
+	self.MarkDirty()
+}
+
 func (self *TabPane) FocusLeft() {
 	if self.ActiveTabIndex > 0 {
 		self.ActiveTabIndex--
 	}
+	self.MarkDirty()
 }
 
 func (self *TabPane) FocusRight() {
 	if self.ActiveTabIndex < len(self.TabNames)-1 {
 		self.ActiveTabIndex++
 	}
+	self.MarkDirty()
 }
 
 func (self *TabPane) Draw(buf *Buffer) {
@@ -57,7 +67,7 @@ func (self *TabPane) Draw(buf *Buffer) {
 			image.Pt(xCoordinate, self.Inner.Min.Y),
 		)
 
-		xCoordinate += 1 + len(name)
+		xCoordinate += 1 + StringWidth(name)
 
 		if i < len(self.TabNames)-1 && xCoordinate < self.Inner.Max.X {
 			buf.SetCell(
@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	. "github.com/sparques/termui/v3"
-	rw "github.com/mattn/go-runewidth"
 )
 
 const treeIndent = "  "
@@ -76,6 +75,7 @@ func (self *Tree) prepareNodes() {
 	for _, node := range self.nodes {
 		self.prepareNode(node, 0)
 	}
+	self.MarkDirty()
 }
 
 func (self *Tree) prepareNode(node *TreeNode, level int) {
@@ -136,8 +136,10 @@ func (self *Tree) Draw(buf *Buffer) {
 			if point.X+1 == self.Inner.Max.X+1 && len(cells) > self.Inner.Dx() {
 				buf.SetCell(NewCell(ELLIPSES, style), point.Add(image.Pt(-1, 0)))
 			} else {
-				buf.SetCell(NewCell(cells[j].Rune, style), point)
-				point = point.Add(image.Pt(rw.RuneWidth(cells[j].Rune), 0))
+				cell := cells[j]
+				cell.Style = style
+				buf.SetCell(cell, point)
+				point = point.Add(image.Pt(cell.Width(), 0))
 			}
 		}
 		point = image.Pt(self.Inner.Min.X, point.Y+1)
@@ -171,6 +173,7 @@ func (self *Tree) ScrollAmount(amount int) {
 	} else {
 		self.SelectedRow += amount
 	}
+	self.MarkDirty()
 }
 
 func (self *Tree) SelectedNode() *TreeNode {
@@ -192,6 +195,7 @@ func (self *Tree) ScrollPageUp() {
 	// If an item is selected below top row, then go to the top row.
 	if self.SelectedRow > self.topRow {
 		self.SelectedRow = self.topRow
+		self.MarkDirty()
 	} else {
 		self.ScrollAmount(-self.Inner.Dy())
 	}
@@ -211,10 +215,12 @@ func (self *Tree) ScrollHalfPageDown() {
 
 func (self *Tree) ScrollTop() {
 	self.SelectedRow = 0
+	self.MarkDirty()
 }
 
 func (self *Tree) ScrollBottom() {
 	self.SelectedRow = len(self.rows) - 1
+	self.MarkDirty()
 }
 
 func (self *Tree) Collapse() {
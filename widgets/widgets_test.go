@@ -0,0 +1,160 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package widgets_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	ui "github.com/sparques/termui/v3"
+	"github.com/sparques/termui/v3/termuitest"
+	"github.com/sparques/termui/v3/widgets"
+)
+
+type nodeValue string
+
+func (self nodeValue) String() string {
+	return string(self)
+}
+
+func TestParagraphGolden(t *testing.T) {
+	p := widgets.NewParagraph()
+	p.Title = "Paragraph"
+	p.Text = "Hello [World](fg:red,mod:bold)!\n世界\nThis is a long line that wraps."
+	termuitest.AssertDrawable(t, "paragraph", p, 24, 6, termuitest.FormatStyled)
+}
+
+func TestListGolden(t *testing.T) {
+	l := widgets.NewList()
+	l.Title = "List"
+	l.Rows = []string{"[0] first", "[1] [second](fg:green)", "[2] third", "[3] fourth", "[4] fifth"}
+	l.SelectedRow = 3
+	l.SelectedRowStyle = ui.NewStyle(ui.ColorYellow, ui.ColorClear, ui.ModifierBold)
+	termuitest.AssertDrawable(t, "list", l, 20, 5, termuitest.FormatStyled)
+}
+
+func TestGaugeGolden(t *testing.T) {
+	g := widgets.NewGauge()
+	g.Title = "Gauge"
+	g.Percent = 0.5
+	g.Label = "50% 世界"
+	g.BarColor = ui.ColorBlue
+	termuitest.AssertDrawable(t, "gauge", g, 24, 3, termuitest.FormatStyled)
+}
+
+func TestBarChartGolden(t *testing.T) {
+	bc := widgets.NewBarChart()
+	bc.Title = "Bar Chart"
+	bc.Data = []float64{3, 2, 5, 3, 9}
+	bc.Labels = []string{"S0", "S1", "S2", "S3", "S4"}
+	bc.BarWidth = 3
+	bc.BarColors = []ui.Color{ui.ColorRed, ui.ColorGreen}
+	bc.LabelStyles = []ui.Style{ui.NewStyle(ui.ColorBlue)}
+	bc.NumStyles = []ui.Style{ui.NewStyle(ui.ColorYellow)}
+	termuitest.AssertDrawable(t, "barchart", bc, 24, 10, termuitest.FormatStyled)
+}
+
+func TestStackedBarChartGolden(t *testing.T) {
+	sbc := widgets.NewStackedBarChart()
+	sbc.Title = "Stacked Bar Chart"
+	sbc.Data = [][]float64{{4, 2}, {1, 5}, {3, 3}}
+	sbc.Labels = []string{"A", "B", "C"}
+	sbc.BarWidth = 4
+	termuitest.AssertDrawable(t, "stacked_barchart", sbc, 24, 10, termuitest.FormatStyled)
+}
+
+func TestSparklineGolden(t *testing.T) {
+	sl := widgets.NewSparkline()
+	sl.Title = "Sparkline"
+	sl.Data = []float64{4, 2, 1, 6, 3, 9, 1, 4, 2, 15, 14, 9, 8, 6, 10, 13, 15, 12, 10, 5}
+	sl.LineColor = ui.ColorGreen
+	slg := widgets.NewSparklineGroup(sl)
+	slg.Title = "Group"
+	termuitest.AssertDrawable(t, "sparkline", slg, 24, 6, termuitest.FormatStyled)
+}
+
+func TestTableGolden(t *testing.T) {
+	table := widgets.NewTable()
+	table.Title = "Table"
+	table.Rows = [][]string{
+		{"header 1", "header 2"},
+		{"cell 1", "世界"},
+		{"[cell 3](fg:red)", "cell 4"},
+	}
+	table.RowStyles[0] = ui.NewStyle(ui.ColorWhite, ui.ColorBlack, ui.ModifierBold)
+	termuitest.AssertDrawable(t, "table", table, 24, 7, termuitest.FormatStyled)
+}
+
+func TestTabPaneGolden(t *testing.T) {
+	tabs := widgets.NewTabPane("one", "two", "three")
+	tabs.Title = "Tabs"
+	tabs.ActiveTabIndex = 1
+	termuitest.AssertDrawable(t, "tabs", tabs, 24, 3, termuitest.FormatStyled)
+}
+
+func TestTabContainerGolden(t *testing.T) {
+	first := widgets.NewParagraph()
+	first.Title = "first"
+	first.Text = "first tab"
+	second := widgets.NewParagraph()
+	second.Title = "second"
+	second.Text = "second tab"
+	tc := widgets.NewTabContainer(first, second)
+	tc.SetActiveTab(1)
+	termuitest.AssertDrawable(t, "tab_container", tc, 24, 6, termuitest.FormatStyled)
+}
+
+func TestPlotGolden(t *testing.T) {
+	plot := widgets.NewPlot()
+	plot.Title = "Plot"
+	plot.Data = [][]float64{{1, 3, 2, 5, 4, 6, 5, 8, 7, 9}}
+	plot.LineColors = []ui.Color{ui.ColorCyan}
+	termuitest.AssertDrawable(t, "plot", plot, 30, 10, termuitest.FormatStyled)
+}
+
+func TestScatterPlotGolden(t *testing.T) {
+	plot := widgets.NewPlot()
+	plot.Title = "Scatter"
+	plot.PlotType = widgets.ScatterPlot
+	plot.Marker = widgets.MarkerDot
+	plot.Data = [][]float64{{1, 3, 2, 5, 4, 6, 5, 8, 7, 9}}
+	termuitest.AssertDrawable(t, "scatter_plot", plot, 30, 10, termuitest.FormatStyled)
+}
+
+func TestPieChartGolden(t *testing.T) {
+	pc := widgets.NewPieChart()
+	pc.Title = "Pie Chart"
+	pc.Data = []float64{1, 2, 3}
+	termuitest.AssertDrawable(t, "piechart", pc, 24, 13, termuitest.FormatStyled)
+}
+
+func TestTreeGolden(t *testing.T) {
+	tree := widgets.NewTree()
+	tree.Title = "Tree"
+	tree.SetNodes([]*widgets.TreeNode{
+		{
+			Value: nodeValue("root"),
+			Nodes: []*widgets.TreeNode{
+				{Value: nodeValue("leaf 1")},
+				{Value: nodeValue("branch"), Nodes: []*widgets.TreeNode{{Value: nodeValue("leaf 2")}}},
+			},
+		},
+		{Value: nodeValue("other")},
+	})
+	tree.ExpandAll()
+	tree.SelectedRow = 2
+	termuitest.AssertDrawable(t, "tree", tree, 20, 7, termuitest.FormatStyled)
+}
+
+func TestImageGolden(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, color.RGBA{uint8(x * 32), uint8(y * 32), 128, 255})
+		}
+	}
+	termuitest.AssertDrawable(t, "image", widgets.NewImage(img), 10, 6, termuitest.FormatText)
+}